@@ -21,7 +21,10 @@ import (
 	"gopkg.in/telebot.v4/middleware"
 )
 
-const notifierCheckInterval = 5 * time.Hour
+const (
+	dashboardRefreshInterval = 10 * time.Minute
+	menuCleanupInterval      = time.Minute
+)
 
 func main() {
 	logger := log.Zap()
@@ -57,27 +60,62 @@ func main() {
 
 	logger.Info("init gitlab client...")
 
-	handler := telegram.NewHandler(bot, repo, gitlabClient)
+	messages, err := telegram.NewMessages(cfg)
+	if err != nil {
+		logger.Fatalf("failed to compile message templates: %v", err)
+	}
+
+	logger.Info("init message templates...")
+
+	handler := telegram.NewHandler(
+		bot,
+		repo,
+		gitlabClient,
+		messages,
+		telegram.WithDefaultRole(cfg.Bot.DefaultRole),
+		telegram.WithMenuTTL(cfg.Bot.MenuTTL),
+		telegram.WithConfirm(cfg.Bot.Confirm...),
+		telegram.WithCommandDescriptions(cfg.Bot.RawCommands),
+		telegram.WithWorkingHours(cfg.Bot.WorkingHours.Schedule, cfg.Bot.WorkingHours.CountHeld),
+	)
 
 	initHandlers(bot, cfg, handler)
 
 	logger.Info("init cmd handlers...")
 
-	info := telegram.ChatInfo
-
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	notifier := workers.NewNotifier(
 		handler,
-		handler.Notify(info.ChatID),
-		100*time.Hour,
+		handler.Notify(),
+		cfg.Bot.Reminders,
 	)
 
-	go notifier.Start(ctx, notifierCheckInterval)
+	go notifier.Start(ctx, cfg.Bot.Reminders.CheckInterval)
 
 	logger.Info("init notifier...")
 
+	dashboard := workers.NewDashboardRefresher(handler)
+
+	go dashboard.Start(ctx, dashboardRefreshInterval)
+
+	logger.Info("init dashboard refresher...")
+
+	menus := workers.NewMenuJanitor(handler)
+
+	go menus.Start(ctx, menuCleanupInterval)
+
+	logger.Info("init menu janitor...")
+
+	digest := workers.NewDigest(handler, cfg.Bot.Digest)
+
+	if cfg.Bot.Digest.Enabled() {
+		go digest.Start(ctx)
+
+		logger.Info("init digest...")
+	}
+
 	bot.Tele().Start()
 
 	logger.Info("bot started...")
@@ -90,6 +128,11 @@ func main() {
 	go func() {
 		<-c
 		notifier.Stop()
+		dashboard.Stop()
+		menus.Stop()
+		if cfg.Bot.Digest.Enabled() {
+			digest.Stop()
+		}
 		cancel()
 		bot.Tele().Stop()
 		db.Close()
@@ -145,10 +188,11 @@ func initHandlers(
 	cfg *config.Config,
 	handler *telegram.Handler,
 ) {
-	bot.Tele().Use(telegram.ChatInfoMiddleware)
+	bot.Tele().Use(telegram.TeamChatMiddleware(handler))
 	bot.Tele().Use(middleware.Recover())
-
-	bot.Tele().SetCommands(config.TeleCommands)
+	bot.Tele().Use(telegram.TrackUserMiddleware(handler))
+	bot.Tele().Use(telegram.LanguageMiddleware(handler))
+	bot.Tele().Use(telegram.RoleMiddleware(handler))
 
 	bot.Tele().Handle(
 		telebot.OnUserJoined,
@@ -162,13 +206,13 @@ func initHandlers(
 		telegram.UserLeftMiddleware(handler),
 	)
 
+	bot.Tele().Handle(telebot.OnChatMember, handler.ChatMember)
+
 	bot.Tele().Handle(telebot.OnCallback, handler.HandleCallbacks)
 
-	for command, h := range handler.CallbackHandlers() {
-		bot.Tele().Handle(command, h)
-	}
+	bot.Tele().Handle(telebot.OnQuery, handler.InlineQuery)
 
-	for command, h := range handler.CommandHandlers() {
-		bot.Tele().Handle(command, h)
+	if err := handler.RegisterCommands(); err != nil {
+		log.Zap().Errorf("failed to register commands: %v", err)
 	}
 }
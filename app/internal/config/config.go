@@ -4,17 +4,26 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/joho/godotenv"
-	"gopkg.in/telebot.v4"
+	"github.com/tibeahx/claimer/pkg/entity"
 	"gopkg.in/yaml.v3"
 )
 
 var config *Config
 
 const (
-	botTokenKey    = "BOT_TOKEN"
-	gitlabTokenKey = "GITLAB_TOKEN"
+	botTokenKey      = "BOT_TOKEN"
+	gitlabTokenKey   = "GITLAB_TOKEN"
+	webhookSecretKey = "WEBHOOK_SECRET"
+)
+
+// modes of receiving updates from telegram
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
 )
 
 type Config struct {
@@ -45,41 +54,54 @@ type GitlabConfig struct {
 }
 
 type BotConfig struct {
+	// command name -> description overriding the default one in menus
 	RawCommands map[string]string `yaml:"commands"`
 	Stands      []string          `yaml:"stands"`
 	Token       string            `yaml:"bot_token"`
 	Verbose     bool              `yaml:"verbose"`
+	// language code -> message key -> text/template overriding the default one
+	Templates map[string]map[string]string `yaml:"templates"`
+	// role of users the chat has no role for: admin, member or viewer
+	DefaultRole string `yaml:"default_role"`
+	// actions asked to be confirmed first, any of ConfirmActions
+	Confirm []string `yaml:"confirm"`
+	// how long keyboards sent in reply to commands live, 10m by default
+	MenuTTL time.Duration `yaml:"menu_ttl"`
+	Digest  DigestConfig  `yaml:"digest"`
+	// escalating reminders about stands held for too long
+	Reminders RemindersConfig `yaml:"reminders"`
+	// reminders and digests are deferred till working hours, per chat
+	// ones are set with /working_hours
+	WorkingHours WorkingHoursConfig `yaml:"working_hours"`
+	// polling (default) or webhook
+	Mode    string        `yaml:"mode"`
+	Webhook WebhookConfig `yaml:"webhook"`
 }
 
-var TeleCommands []telebot.Command
-
-var defaultCommands = []telebot.Command{
-	{Text: "/claim", Description: "Claim a stand"},
-	{Text: "/release", Description: "Release currently claimed stand"},
-	{Text: "/list", Description: "Show all stands"},
-	{Text: "/ping", Description: "Ping current stand owner by username"},
-	{Text: "/features_state", Description: "Show current state of features"},
+type WebhookConfig struct {
+	// address to listen on, e.g. :8443
+	Listen string `yaml:"listen"`
+	// url telegram sends updates to, its path is served by the listener
+	PublicURL string `yaml:"public_url"`
+	// TLS is terminated by the bot if both are set, otherwise by a proxy
+	TLSCert string `yaml:"tls_cert"`
+	TLSKey  string `yaml:"tls_key"`
+	// upload tls_cert to telegram, needed for self-signed certificates
+	UploadCert bool `yaml:"upload_cert"`
+	// taken from env, telegram sends it in every request
+	Secret string `yaml:"-"`
 }
 
-// in case we need to set custom commands from config.yaml
-func (c *Config) teleCommandFromRaw() []telebot.Command {
-	result := make([]telebot.Command, 0)
-
-	if len(c.Bot.RawCommands) == 0 {
-		TeleCommands = defaultCommands
-	}
-
-	for cmd, desc := range c.Bot.RawCommands {
-		TeleCommands = append(TeleCommands, telebot.Command{
-			Text:        cmd,
-			Description: desc,
-		})
-	}
-
-	return result
-}
+// ConfirmActions may be configured to ask for confirmation
+var ConfirmActions = []string{"release", "force_release", "transfer"}
 
-var errEmptyToken = errors.New("bot token is empty")
+var (
+	errEmptyToken  = errors.New("bot token is empty")
+	errUnknownRole = errors.New("unknown default role")
+	errUnknownMode = errors.New("unknown bot mode")
+	errConfirm     = errors.New("unknown action to confirm")
+	errWebhook     = errors.New("webhook mode needs listen, public_url and secret")
+)
 
 func load(cfgPath string) error {
 	cfgFileBytes, err := os.ReadFile(cfgPath)
@@ -93,19 +115,57 @@ func load(cfgPath string) error {
 		return fmt.Errorf("failed to parse fileBytes due to %w", err)
 	}
 
-	cfg.teleCommandFromRaw()
-
 	if err := godotenv.Load(); err != nil {
 		return fmt.Errorf("failed to load env due to %w", err)
 	}
 
 	cfg.Bot.Token = os.Getenv(botTokenKey)
 	cfg.Gitlab.Token = os.Getenv(gitlabTokenKey)
+	cfg.Bot.Webhook.Secret = os.Getenv(webhookSecretKey)
 
 	if cfg.Bot.Token == "" {
 		return errEmptyToken
 	}
 
+	if cfg.Bot.DefaultRole == "" {
+		cfg.Bot.DefaultRole = entity.RoleMember
+	}
+
+	if !entity.IsRole(cfg.Bot.DefaultRole) {
+		return fmt.Errorf("%w: %s", errUnknownRole, cfg.Bot.DefaultRole)
+	}
+
+	for _, action := range cfg.Bot.Confirm {
+		if !slices.Contains(ConfirmActions, action) {
+			return fmt.Errorf("%w: %s", errConfirm, action)
+		}
+	}
+
+	if err := cfg.Bot.Digest.parse(); err != nil {
+		return err
+	}
+
+	if err := cfg.Bot.WorkingHours.parse(); err != nil {
+		return err
+	}
+
+	if err := cfg.Bot.Reminders.parse(); err != nil {
+		return err
+	}
+
+	switch cfg.Bot.Mode {
+	case "":
+		cfg.Bot.Mode = ModePolling
+	case ModePolling:
+	case ModeWebhook:
+		hook := cfg.Bot.Webhook
+		if hook.Listen == "" || hook.PublicURL == "" || hook.Secret == "" {
+			return errWebhook
+		}
+	default:
+		return fmt.Errorf("%w: %s", errUnknownMode, cfg.Bot.Mode)
+	}
+
 	config = cfg
 
 	return nil
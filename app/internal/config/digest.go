@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/workhours"
+)
+
+type DigestConfig struct {
+	// time of the digest as HH:MM, the digest is off if empty
+	At string `yaml:"at"`
+	// IANA time zone of the team, e.g. Europe/Moscow, local one if empty
+	Timezone string `yaml:"timezone"`
+	// mon, tue, ..., sun; every day if empty
+	Weekdays []string `yaml:"weekdays"`
+
+	Hour     int            `yaml:"-"`
+	Minute   int            `yaml:"-"`
+	Location *time.Location `yaml:"-"`
+	Days     []time.Weekday `yaml:"-"`
+}
+
+var errDigest = errors.New("invalid digest schedule")
+
+// Enabled reports whether the digest is scheduled
+func (d DigestConfig) Enabled() bool {
+	return d.At != ""
+}
+
+func (d *DigestConfig) parse() error {
+	if !d.Enabled() {
+		return nil
+	}
+
+	at, err := time.Parse("15:04", d.At)
+	if err != nil {
+		return fmt.Errorf("%w: at %q", errDigest, d.At)
+	}
+
+	d.Hour, d.Minute = at.Hour(), at.Minute()
+
+	d.Location = time.Local
+	if d.Timezone != "" {
+		if d.Location, err = time.LoadLocation(d.Timezone); err != nil {
+			return fmt.Errorf("%w: %w", errDigest, err)
+		}
+	}
+
+	if d.Days, err = workhours.ParseWeekdays(d.Weekdays); err != nil {
+		return fmt.Errorf("%w: %w", errDigest, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDigestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		digest   DigestConfig
+		hour     int
+		minute   int
+		location string
+		days     []time.Weekday
+		wantErr  bool
+	}{
+		{name: "disabled", digest: DigestConfig{}},
+		{name: "every day", digest: DigestConfig{At: "10:30"}, hour: 10, minute: 30, location: "Local"},
+		{
+			name:     "weekdays in time zone",
+			digest:   DigestConfig{At: "09:00", Timezone: "UTC", Weekdays: []string{"Mon", "fri"}},
+			hour:     9,
+			location: "UTC",
+			days:     []time.Weekday{time.Monday, time.Friday},
+		},
+		{name: "bad time", digest: DigestConfig{At: "25:00"}, wantErr: true},
+		{name: "bad time zone", digest: DigestConfig{At: "09:00", Timezone: "Nowhere/City"}, wantErr: true},
+		{name: "bad weekday", digest: DigestConfig{At: "09:00", Weekdays: []string{"funday"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.digest.parse()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("parse succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+
+			if !tt.digest.Enabled() {
+				return
+			}
+
+			d := tt.digest
+			if d.Hour != tt.hour || d.Minute != tt.minute || d.Location.String() != tt.location {
+				t.Errorf("got %02d:%02d %s, want %02d:%02d %s", d.Hour, d.Minute, d.Location, tt.hour, tt.minute, tt.location)
+			}
+			if len(d.Days) != len(tt.days) || (len(tt.days) > 0 && !slices.Equal(d.Days, tt.days)) {
+				t.Errorf("got days %v, want %v", d.Days, tt.days)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"time"
+)
+
+// whom a reminder tier notifies
+const (
+	// owner in private messages or the group, as they chose in /settings
+	NotifyOwner = "owner"
+	// owner is mentioned in the group
+	NotifyGroup = "group"
+	// chat admins are mentioned in the group together with the owner
+	NotifyAdmins = "admins"
+	// owner is warned and the stand is released after the grace period
+	// unless they keep it
+	NotifyRelease = "release"
+)
+
+const (
+	// defaultReleaseGrace is how long owner has to keep the stand
+	defaultReleaseGrace = time.Hour
+	// defaultCheckInterval is how often stands are checked for reminders
+	defaultCheckInterval = 30 * time.Minute
+)
+
+// defaultTiers are used if none are configured
+var defaultTiers = []ReminderTier{{After: 100 * time.Hour, Notify: NotifyOwner}}
+
+type RemindersConfig struct {
+	// how often held stands are checked, 30m by default. Tiers fire no
+	// sooner than the check after they are due
+	CheckInterval time.Duration `yaml:"check_interval"`
+	// escalation tiers of all stands, each fires once per claim
+	Tiers []ReminderTier `yaml:"tiers"`
+	// stand name or pattern, e.g. dev-*, -> tiers overriding the default ones
+	Stands map[string][]ReminderTier `yaml:"stands"`
+}
+
+type ReminderTier struct {
+	// time the stand is held for before the tier fires
+	After time.Duration `yaml:"after"`
+	// owner, group, admins or release
+	Notify string `yaml:"notify"`
+	// time owner has to keep the stand before it's released, release
+	// tier only, 1h by default
+	Grace time.Duration `yaml:"grace"`
+
+	// number of the tier starting from 1, stands remember the last fired one
+	Level int `yaml:"-"`
+}
+
+var errReminders = errors.New("invalid reminder tiers")
+
+// For returns tiers of the stand: tiers of its name, of the first pattern
+// it matches in lexical order or the default ones
+func (r RemindersConfig) For(standName string) []ReminderTier {
+	if tiers, ok := r.Stands[standName]; ok {
+		return tiers
+	}
+
+	patterns := make([]string, 0, len(r.Stands))
+	for pattern := range r.Stands {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, standName); ok {
+			return r.Stands[pattern]
+		}
+	}
+
+	return r.Tiers
+}
+
+func (r *RemindersConfig) parse() error {
+	switch {
+	case r.CheckInterval < 0:
+		return fmt.Errorf("%w: check_interval must be positive", errReminders)
+	case r.CheckInterval == 0:
+		r.CheckInterval = defaultCheckInterval
+	}
+
+	if len(r.Tiers) == 0 {
+		r.Tiers = slices.Clone(defaultTiers)
+	}
+
+	if err := parseTiers(r.Tiers); err != nil {
+		return err
+	}
+
+	for pattern, tiers := range r.Stands {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: stand %q: %w", errReminders, pattern, err)
+		}
+
+		if len(tiers) == 0 {
+			return fmt.Errorf("%w: stand %q has no tiers", errReminders, pattern)
+		}
+
+		if err := parseTiers(tiers); err != nil {
+			return fmt.Errorf("stand %q: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// parseTiers checks tiers and numbers them in order they fire
+func parseTiers(tiers []ReminderTier) error {
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].After < tiers[j].After
+	})
+
+	for i := range tiers {
+		switch tiers[i].Notify {
+		case NotifyOwner, NotifyGroup, NotifyAdmins:
+		case NotifyRelease:
+			if tiers[i].Grace <= 0 {
+				tiers[i].Grace = defaultReleaseGrace
+			}
+		case "":
+			tiers[i].Notify = NotifyOwner
+		default:
+			return fmt.Errorf("%w: unknown notify %q", errReminders, tiers[i].Notify)
+		}
+
+		if tiers[i].After <= 0 {
+			return fmt.Errorf("%w: after must be positive", errReminders)
+		}
+
+		tiers[i].Level = i + 1
+	}
+
+	return nil
+}
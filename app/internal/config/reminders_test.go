@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRemindersFor(t *testing.T) {
+	var (
+		defaults = []ReminderTier{{After: 24 * time.Hour, Notify: NotifyOwner}}
+		prod     = []ReminderTier{{After: 4 * time.Hour, Notify: NotifyAdmins}}
+		prodAny  = []ReminderTier{{After: 8 * time.Hour, Notify: NotifyGroup}}
+		prodEU   = []ReminderTier{{After: 12 * time.Hour, Notify: NotifyRelease}}
+		devAny   = []ReminderTier{{After: 48 * time.Hour, Notify: NotifyGroup}}
+	)
+
+	reminders := RemindersConfig{
+		Tiers: defaults,
+		Stands: map[string][]ReminderTier{
+			"prod":     prod,
+			"prod-*":   prodAny,
+			"prod-eu*": prodEU,
+			"dev-?":    devAny,
+		},
+	}
+
+	tests := []struct {
+		stand string
+		want  []ReminderTier
+	}{
+		{stand: "prod", want: prod},
+		// both patterns match, the first one in lexical order wins
+		{stand: "prod-eu1", want: prodAny},
+		{stand: "prod-us", want: prodAny},
+		{stand: "dev-1", want: devAny},
+		{stand: "dev-12", want: defaults},
+		{stand: "stage", want: defaults},
+		{stand: "", want: defaults},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stand, func(t *testing.T) {
+			got := reminders.For(tt.stand)
+			if len(got) != 1 || got[0] != tt.want[0] {
+				t.Errorf("For(%q) = %+v, want %+v", tt.stand, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		tiers   []ReminderTier
+		want    []ReminderTier
+		wantErr bool
+	}{
+		{
+			name: "sorted by after",
+			tiers: []ReminderTier{
+				{After: 72 * time.Hour, Notify: NotifyAdmins},
+				{After: 24 * time.Hour, Notify: NotifyOwner},
+				{After: 48 * time.Hour, Notify: NotifyGroup},
+			},
+			want: []ReminderTier{
+				{After: 24 * time.Hour, Notify: NotifyOwner, Level: 1},
+				{After: 48 * time.Hour, Notify: NotifyGroup, Level: 2},
+				{After: 72 * time.Hour, Notify: NotifyAdmins, Level: 3},
+			},
+		},
+		{
+			name: "equal after keep their order",
+			tiers: []ReminderTier{
+				{After: 24 * time.Hour, Notify: NotifyGroup},
+				{After: 24 * time.Hour, Notify: NotifyOwner},
+			},
+			want: []ReminderTier{
+				{After: 24 * time.Hour, Notify: NotifyGroup, Level: 1},
+				{After: 24 * time.Hour, Notify: NotifyOwner, Level: 2},
+			},
+		},
+		{
+			name:  "owner by default",
+			tiers: []ReminderTier{{After: time.Hour}},
+			want:  []ReminderTier{{After: time.Hour, Notify: NotifyOwner, Level: 1}},
+		},
+		{
+			name:  "release grace by default",
+			tiers: []ReminderTier{{After: time.Hour, Notify: NotifyRelease}},
+			want:  []ReminderTier{{After: time.Hour, Notify: NotifyRelease, Grace: defaultReleaseGrace, Level: 1}},
+		},
+		{
+			name:  "release grace kept",
+			tiers: []ReminderTier{{After: time.Hour, Notify: NotifyRelease, Grace: 2 * time.Hour}},
+			want:  []ReminderTier{{After: time.Hour, Notify: NotifyRelease, Grace: 2 * time.Hour, Level: 1}},
+		},
+		{
+			name:    "unknown notify",
+			tiers:   []ReminderTier{{After: time.Hour, Notify: "everyone"}},
+			wantErr: true,
+		},
+		{
+			name:    "zero after",
+			tiers:   []ReminderTier{{Notify: NotifyOwner}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseTiers(tt.tiers)
+			if tt.wantErr {
+				if !errors.Is(err, errReminders) {
+					t.Fatalf("got error %v, want %v", err, errReminders)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTiers failed: %v", err)
+			}
+
+			if len(tt.tiers) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", tt.tiers, tt.want)
+			}
+			for i := range tt.want {
+				if tt.tiers[i] != tt.want[i] {
+					t.Errorf("tier %d = %+v, want %+v", i, tt.tiers[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRemindersCheckInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     time.Duration
+		wantErr  bool
+	}{
+		{name: "default", want: defaultCheckInterval},
+		{name: "configured", interval: 5 * time.Minute, want: 5 * time.Minute},
+		{name: "negative", interval: -time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminders := RemindersConfig{CheckInterval: tt.interval}
+
+			err := reminders.parse()
+			if tt.wantErr {
+				if !errors.Is(err, errReminders) {
+					t.Fatalf("got error %v, want %v", err, errReminders)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+
+			if reminders.CheckInterval != tt.want {
+				t.Errorf("got %s, want %s", reminders.CheckInterval, tt.want)
+			}
+		})
+	}
+}
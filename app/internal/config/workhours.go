@@ -0,0 +1,24 @@
+package config
+
+import (
+	"github.com/tibeahx/claimer/app/internal/workhours"
+)
+
+type WorkingHoursConfig struct {
+	// working hours as HH:MM-HH:MM, reminders are sent any time if empty
+	Hours string `yaml:"hours"`
+	// IANA time zone of the team, e.g. Europe/Moscow, local one if empty
+	Timezone string `yaml:"timezone"`
+	// mon, tue, ..., sun; every day if empty
+	Weekdays []string `yaml:"weekdays"`
+	// count only working hours stands are held for when checking reminder
+	// thresholds
+	CountHeld bool `yaml:"count_held"`
+
+	Schedule workhours.Hours `yaml:"-"`
+}
+
+func (w *WorkingHoursConfig) parse() (err error) {
+	w.Schedule, err = workhours.New(w.Hours, w.Weekdays, w.Timezone)
+	return err
+}
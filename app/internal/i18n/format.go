@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// durationFunc formats d as "2d 3h", "3h 5m" or "45m", units are day, hour
+// and minute suffixes and default to english ones
+func durationFunc(d time.Duration, units ...string) string {
+	suffixes := []string{"d", "h", "m"}
+	copy(suffixes, units)
+
+	if d < 0 {
+		d = 0
+	}
+
+	var (
+		days    = int(d / (24 * time.Hour))
+		hours   = int(d % (24 * time.Hour) / time.Hour)
+		minutes = int(d % time.Hour / time.Minute)
+	)
+
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%d%s %d%s", days, suffixes[0], hours, suffixes[1])
+	case days > 0:
+		return fmt.Sprintf("%d%s", days, suffixes[0])
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%d%s %d%s", hours, suffixes[1], minutes, suffixes[2])
+	case hours > 0:
+		return fmt.Sprintf("%d%s", hours, suffixes[1])
+	default:
+		return fmt.Sprintf("%d%s", minutes, suffixes[2])
+	}
+}
+
+// sinceFunc formats t relative to now: "18:04" for today, "yesterday 18:04"
+// with given word for yesterday and layout for anything older
+func sinceFunc(t time.Time, yesterday, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	t = t.Local()
+	now := time.Now()
+
+	y, m, d := t.Date()
+	ny, nm, nd := now.Date()
+
+	if y == ny && m == nm && d == nd {
+		return t.Format("15:04")
+	}
+
+	py, pm, pd := now.AddDate(0, 0, -1).Date()
+	if y == py && m == pm && d == pd {
+		return yesterday + " " + t.Format("15:04")
+	}
+
+	return t.Format(layout)
+}
+
+// padFunc right-pads s with spaces up to width runes, so columns line up
+// in monospace text
+func padFunc(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	return s
+}
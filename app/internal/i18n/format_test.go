@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationFunc(t *testing.T) {
+	tests := []struct {
+		name  string
+		d     time.Duration
+		units []string
+		want  string
+	}{
+		{name: "negative", d: -time.Hour, want: "0m"},
+		{name: "zero", d: 0, want: "0m"},
+		{name: "minutes", d: 45 * time.Minute, want: "45m"},
+		{name: "seconds are dropped", d: 59 * time.Second, want: "0m"},
+		{name: "hours", d: 3 * time.Hour, want: "3h"},
+		{name: "hours and minutes", d: 3*time.Hour + 5*time.Minute, want: "3h 5m"},
+		{name: "days", d: 48 * time.Hour, want: "2d"},
+		{name: "days and hours", d: 51*time.Hour + 30*time.Minute, want: "2d 3h"},
+		{name: "russian units", d: 27 * time.Hour, units: []string{"д", "ч", "м"}, want: "1д 3ч"},
+		{name: "partial units", d: 90 * time.Minute, units: []string{"d"}, want: "1h 30m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := durationFunc(tt.d, tt.units...); got != tt.want {
+				t.Errorf("durationFunc(%s) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSinceFunc(t *testing.T) {
+	var (
+		now       = time.Now()
+		today     = time.Date(now.Year(), now.Month(), now.Day(), 9, 5, 0, 0, time.Local)
+		yesterday = today.AddDate(0, 0, -1)
+		older     = today.AddDate(0, 0, -3)
+	)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{name: "zero", t: time.Time{}, want: ""},
+		{name: "today", t: today, want: "09:05"},
+		{name: "yesterday", t: yesterday, want: "yesterday 09:05"},
+		{name: "older", t: older, want: older.Format("02.01 15:04")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sinceFunc(tt.t, "yesterday", "02.01 15:04"); got != tt.want {
+				t.Errorf("sinceFunc(%s) = %q, want %q", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPadFunc(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{s: "dev", width: 6, want: "dev   "},
+		{s: "стенд", width: 6, want: "стенд "},
+		{s: "release-stage", width: 6, want: "release-stage"},
+		{s: "", width: 2, want: "  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := padFunc(tt.s, tt.width); got != tt.want {
+				t.Errorf("padFunc(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+		})
+	}
+}
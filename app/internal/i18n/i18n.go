@@ -0,0 +1,170 @@
+package i18n
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/tibeahx/claimer/pkg/log"
+)
+
+type Lang string
+
+const (
+	English Lang = "en"
+	Russian Lang = "ru"
+
+	Default = English
+)
+
+var supported = []Lang{English, Russian}
+
+func Supported() []Lang {
+	return supported
+}
+
+// Parse returns supported language by its code, e.g. "ru" or "RU"
+func Parse(code string) (Lang, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	for _, lang := range supported {
+		if string(lang) == code {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+type Key string
+
+// Catalogue holds text/template sources of messages per language
+type Catalogue map[Lang]map[Key]string
+
+// Templates are compiled catalogue messages ready to be rendered
+type Templates struct {
+	tpls map[Lang]map[Key]*template.Template
+}
+
+// Compile parses defaults with overrides applied on top of them. Every
+// template is executed against sample, so a reference to unknown field
+// fails here rather than when the message is sent. Besides funcs,
+// templates may use `plural n "one" "few" "many"`, `join list sep`,
+// `duration d "d" "h" "m"`, `since t "yesterday" layout` and `pad s width`.
+func Compile(defaults, overrides Catalogue, funcs template.FuncMap, sample any) (*Templates, error) {
+	for lang, msgs := range overrides {
+		if _, ok := defaults[lang]; !ok {
+			return nil, fmt.Errorf("unsupported language %q", lang)
+		}
+
+		for key := range msgs {
+			if _, ok := defaults[Default][key]; !ok {
+				return nil, fmt.Errorf("unknown message %q", key)
+			}
+		}
+	}
+
+	sources := make(Catalogue, len(defaults))
+	for lang, msgs := range defaults {
+		sources[lang] = make(map[Key]string, len(msgs))
+		for key, src := range msgs {
+			sources[lang][key] = src
+		}
+		for key, src := range overrides[lang] {
+			sources[lang][key] = src
+		}
+	}
+
+	t := &Templates{
+		tpls: make(map[Lang]map[Key]*template.Template, len(sources)),
+	}
+
+	for lang, msgs := range sources {
+		langFuncs := template.FuncMap{
+			"plural":   pluralFunc(lang),
+			"join":     strings.Join,
+			"duration": durationFunc,
+			"since":    sinceFunc,
+			"pad":      padFunc,
+		}
+		for name, fn := range funcs {
+			langFuncs[name] = fn
+		}
+
+		t.tpls[lang] = make(map[Key]*template.Template, len(msgs))
+
+		for key, src := range msgs {
+			tpl, err := template.New(string(key)).Funcs(langFuncs).Parse(src)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s/%s: %w", lang, key, err)
+			}
+
+			if err := tpl.Execute(&bytes.Buffer{}, sample); err != nil {
+				return nil, fmt.Errorf("failed to execute %s/%s: %w", lang, key, err)
+			}
+
+			t.tpls[lang][key] = tpl
+		}
+	}
+
+	return t, nil
+}
+
+// Text renders message in given language, falls back to the default
+// language and then to the key itself if there is no translation
+func (t *Templates) Text(lang Lang, key Key, data any) string {
+	tpl, ok := t.tpls[lang][key]
+	if !ok {
+		tpl, ok = t.tpls[Default][key]
+	}
+	if !ok {
+		return string(key)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		log.Zap().Errorf("failed to render %s/%s: %v", lang, key, err)
+		return string(key)
+	}
+
+	return buf.String()
+}
+
+// pluralFunc picks one of forms for n by language rules, forms are
+// listed as for russian: one, few, many; english uses first two only
+func pluralFunc(lang Lang) func(n int, forms ...string) string {
+	return func(n int, forms ...string) string {
+		if len(forms) == 0 {
+			return ""
+		}
+
+		idx := pluralIndex(lang, n)
+		if idx >= len(forms) {
+			idx = len(forms) - 1
+		}
+
+		return forms[idx]
+	}
+}
+
+func pluralIndex(lang Lang, n int) int {
+	if n < 0 {
+		n = -n
+	}
+
+	switch lang {
+	case Russian:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
@@ -2,13 +2,17 @@ package repo
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/tibeahx/claimer/pkg/dbutils"
 	"github.com/tibeahx/claimer/pkg/entity"
 )
 
+var ErrStandNotOwned = errors.New("stand is not owned by the user")
+
 type Repo struct {
 	db *sqlx.DB
 }
@@ -25,7 +29,10 @@ select
 	name,
 	released,
 	owner_username,
-	time_claimed
+	time_claimed,
+	reminder_ack_until,
+	reminder_tier,
+	release_at
 from
 	stands
 where
@@ -74,7 +81,10 @@ update stands
 set
 	owner_username = :owner_username,
 	time_claimed = now (),
-	released = false
+	released = false,
+	reminder_ack_until = null,
+	reminder_tier = 0,
+	release_at = null
 where
 	name = :name
 	and released = true
@@ -95,7 +105,8 @@ func (r *Repo) ReleaseStand(stand entity.Stand) error {
 update stands
 set
 	owner_username = null,
-	released = true
+	released = true,
+	release_at = null
 where
 	name = :name
 	and released = false
@@ -167,18 +178,1241 @@ from
 	return canClaim, nil
 }
 
-func (r *Repo) DeleteUser(username string) error {
-	const q = `
+// DeleteUser releases stands of the user and deletes them in one
+// transaction, otherwise the stands would be deleted by cascade. Names
+// of released stands are returned
+func (r *Repo) DeleteUser(username string) ([]string, error) {
+	const (
+		qStands = `
+update stands
+set
+	owner_username = null,
+	released = true,
+	reminder_ack_until = null,
+	reminder_tier = 0,
+	release_at = null
+where
+	owner_username = :username
+returning
+	name
+	`
+		qUser = `
 delete from users
 where
 	username = :username
 	`
+	)
+
+	args := map[string]any{
+		"username": username,
+	}
+
+	var released []string
+
+	err := dbutils.WithTx(r.db, func(tx *sqlx.Tx) error {
+		rows, err := tx.NamedQuery(qStands, args)
+		if err != nil {
+			return fmt.Errorf("failed to release stands: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return fmt.Errorf("failed to scan released stand: %w", err)
+			}
+			released = append(released, name)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to release stands: %w", err)
+		}
+
+		// the connection is busy until rows are closed
+		rows.Close()
+
+		if _, err := tx.NamedExec(qUser, args); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
+
+// ChatUsers returns users the bot has seen in the chat
+func (r *Repo) ChatUsers(chatID int64) ([]entity.User, error) {
+	const q = `
+select
+	username,
+	created,
+	language,
+	user_id,
+	team_chat_id,
+	private_chat,
+	notify_private,
+	quiet_hours
+from
+	users
+where
+	team_chat_id = :chat_id
+	and user_id is not null
+	`
+
+	var users []entity.User
+
+	err := dbutils.NamedSelect(
+		r.db,
+		q,
+		&users,
+		map[string]any{
+			"chat_id": chatID,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users of chat: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *Repo) Dashboards() ([]entity.Dashboard, error) {
+	const q = `
+select
+	chat_id,
+	message_id,
+	updated
+from
+	dashboards
+	`
+
+	var dashboards []entity.Dashboard
+
+	err := dbutils.NamedSelect(
+		r.db,
+		q,
+		&dashboards,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboards: %w", err)
+	}
+
+	return dashboards, nil
+}
+
+func (r *Repo) Dashboard(chatID int64) (entity.Dashboard, bool, error) {
+	const q = `
+select
+	chat_id,
+	message_id,
+	updated
+from
+	dashboards
+where
+	chat_id = :chat_id
+	`
+
+	var dashboard entity.Dashboard
+
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&dashboard,
+		map[string]any{
+			"chat_id": chatID,
+		},
+	)
+	if err == sql.ErrNoRows {
+		return entity.Dashboard{}, false, nil
+	}
+
+	if err != nil {
+		return entity.Dashboard{}, false, fmt.Errorf("failed to get dashboard: %w", err)
+	}
+
+	return dashboard, true, nil
+}
+
+func (r *Repo) SaveDashboard(dashboard entity.Dashboard) error {
+	const q = `
+insert into
+	dashboards (chat_id, message_id, updated)
+values
+	(:chat_id, :message_id, now ()) on conflict (chat_id) do update
+set
+	message_id = excluded.message_id,
+	updated = excluded.updated
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id":    dashboard.ChatID,
+			"message_id": dashboard.MessageID,
+		},
+	)
+}
+
+func (r *Repo) DeleteDashboard(chatID int64) error {
+	const q = `
+delete from dashboards
+where
+	chat_id = :chat_id
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id": chatID,
+		},
+	)
+}
+
+func (r *Repo) SaveMenu(menu entity.Menu) error {
+	const q = `
+insert into
+	menus (chat_id, message_id, command_message_id, expires)
+values
+	(:chat_id, :message_id, :command_message_id, :expires) on conflict (chat_id, message_id) do update
+set
+	command_message_id = excluded.command_message_id,
+	expires = excluded.expires
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id":            menu.ChatID,
+			"message_id":         menu.MessageID,
+			"command_message_id": menu.CommandMessageID,
+			"expires":            menu.Expires,
+		},
+	)
+}
+
+func (r *Repo) Menu(chatID int64, messageID int) (entity.Menu, bool, error) {
+	const q = `
+select
+	chat_id,
+	message_id,
+	command_message_id,
+	expires
+from
+	menus
+where
+	chat_id = :chat_id
+	and message_id = :message_id
+	`
+
+	var menu entity.Menu
+
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&menu,
+		map[string]any{
+			"chat_id":    chatID,
+			"message_id": messageID,
+		},
+	)
+	if err == sql.ErrNoRows {
+		return entity.Menu{}, false, nil
+	}
+
+	if err != nil {
+		return entity.Menu{}, false, fmt.Errorf("failed to get menu: %w", err)
+	}
+
+	return menu, true, nil
+}
+
+// ExpiredMenus returns menus which weren't used in time
+func (r *Repo) ExpiredMenus(now time.Time) ([]entity.Menu, error) {
+	const q = `
+select
+	chat_id,
+	message_id,
+	command_message_id,
+	expires
+from
+	menus
+where
+	expires <= :now
+	`
+
+	var menus []entity.Menu
+
+	err := dbutils.NamedSelect(
+		r.db,
+		q,
+		&menus,
+		map[string]any{
+			"now": now,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired menus: %w", err)
+	}
+
+	return menus, nil
+}
+
+func (r *Repo) DeleteMenu(chatID int64, messageID int) error {
+	const q = `
+delete from menus
+where
+	chat_id = :chat_id
+	and message_id = :message_id
+	`
 
 	return dbutils.NamedExec(
 		r.db,
 		q,
 		map[string]any{
+			"chat_id":    chatID,
+			"message_id": messageID,
+		},
+	)
+}
+
+// Language returns user's language if it's set, otherwise chat's one,
+// empty string means neither is set
+func (r *Repo) Language(chatID int64, username string) (string, error) {
+	const q = `
+select
+	coalesce(
+		(
+			select
+				language
+			from
+				users
+			where
+				username = :username
+		),
+		(
+			select
+				language
+			from
+				chat_settings
+			where
+				chat_id = :chat_id
+		),
+		''
+	) as language
+	`
+
+	var language string
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&language,
+		map[string]any{
+			"chat_id":  chatID,
 			"username": username,
 		},
 	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get language: %w", err)
+	}
+
+	return language, nil
+}
+
+func (r *Repo) SetChatLanguage(chatID int64, language string) error {
+	const q = `
+insert into
+	chat_settings (chat_id, language)
+values
+	(:chat_id, :language) on conflict (chat_id) do update
+set
+	language = excluded.language
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id":  chatID,
+			"language": language,
+		},
+	)
+}
+
+// StandsThread returns forum topic of the chat for notifications and the
+// dashboard, 0 means it isn't set
+func (r *Repo) StandsThread(chatID int64) (int, error) {
+	const q = `
+select
+	coalesce(
+		(
+			select
+				stands_thread_id
+			from
+				chat_settings
+			where
+				chat_id = :chat_id
+		),
+		0
+	) as stands_thread_id
+	`
+
+	var threadID int
+
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&threadID,
+		map[string]any{
+			"chat_id": chatID,
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stands thread: %w", err)
+	}
+
+	return threadID, nil
+}
+
+func (r *Repo) SetStandsThread(chatID int64, threadID int) error {
+	const q = `
+insert into
+	chat_settings (chat_id, stands_thread_id)
+values
+	(:chat_id, :stands_thread_id) on conflict (chat_id) do update
+set
+	stands_thread_id = excluded.stands_thread_id
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id":          chatID,
+			"stands_thread_id": sql.NullInt64{Int64: int64(threadID), Valid: threadID != 0},
+		},
+	)
+}
+
+// TeamChat returns the group chat the bot has seen the last, 0 if there is
+// none yet
+func (r *Repo) TeamChat() (int64, error) {
+	const q = `
+select
+	coalesce(
+		(
+			select
+				chat_id
+			from
+				chat_settings
+			where
+				seen_at is not null
+			order by
+				seen_at desc
+			limit
+				1
+		),
+		0
+	) as chat_id
+	`
+
+	var chatID int64
+
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&chatID,
+		map[string]any{},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get team chat: %w", err)
+	}
+
+	return chatID, nil
+}
+
+// SetTeamChat remembers the group chat the bot has seen the last, so
+// workers know where to post after restart
+func (r *Repo) SetTeamChat(chatID int64) error {
+	const q = `
+insert into
+	chat_settings (chat_id, seen_at)
+values
+	(:chat_id, now()) on conflict (chat_id) do update
+set
+	seen_at = excluded.seen_at
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id": chatID,
+		},
+	)
+}
+
+// WorkingHours returns working hours set for the chat, empty string
+// means they aren't set
+func (r *Repo) WorkingHours(chatID int64) (string, error) {
+	const q = `
+select
+	coalesce(
+		(
+			select
+				working_hours
+			from
+				chat_settings
+			where
+				chat_id = :chat_id
+		),
+		''
+	) as working_hours
+	`
+
+	var hours string
+
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&hours,
+		map[string]any{
+			"chat_id": chatID,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get working hours: %w", err)
+	}
+
+	return hours, nil
+}
+
+// SetWorkingHours sets working hours of the chat, empty string resets
+// them to the configured ones
+func (r *Repo) SetWorkingHours(chatID int64, hours string) error {
+	const q = `
+insert into
+	chat_settings (chat_id, working_hours)
+values
+	(:chat_id, :working_hours) on conflict (chat_id) do update
+set
+	working_hours = excluded.working_hours
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id":       chatID,
+			"working_hours": sql.NullString{String: hours, Valid: hours != ""},
+		},
+	)
+}
+
+func (r *Repo) SetUserLanguage(username string, language string) error {
+	const q = `
+insert into
+	users (username, created, language)
+values
+	(:username, now (), :language) on conflict (username) do update
+set
+	language = excluded.language
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"username": username,
+			"language": language,
+		},
+	)
+}
+
+// TouchUser creates user or updates telegram ids of existing one,
+// zero team chat id keeps the stored one
+func (r *Repo) TouchUser(user entity.User) error {
+	const q = `
+insert into
+	users (username, created, user_id, team_chat_id)
+values
+	(:username, now (), :user_id, nullif(cast(:team_chat_id as bigint), 0)) on conflict (username) do update
+set
+	user_id = excluded.user_id,
+	team_chat_id = coalesce(excluded.team_chat_id, users.team_chat_id)
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"username":     user.Username,
+			"user_id":      user.UserID.Int64,
+			"team_chat_id": user.TeamChatID.Int64,
+		},
+	)
+}
+
+func (r *Repo) Users(usernames []string) ([]entity.User, error) {
+	const q = `
+select
+	username,
+	created,
+	language,
+	user_id,
+	team_chat_id,
+	private_chat,
+	notify_private,
+	quiet_hours
+from
+	users
+where
+	username = any (:usernames)
+	`
+
+	var users []entity.User
+
+	err := dbutils.NamedSelect(
+		r.db,
+		q,
+		&users,
+		map[string]any{
+			"usernames": usernames,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *Repo) SetPrivateChat(username string, enabled bool) error {
+	const q = `
+update users
+set
+	private_chat = :enabled
+where
+	username = :username
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"username": username,
+			"enabled":  enabled,
+		},
+	)
+}
+
+func (r *Repo) SetNotifyPrivate(username string, enabled bool) error {
+	const q = `
+update users
+set
+	notify_private = :enabled
+where
+	username = :username
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"username": username,
+			"enabled":  enabled,
+		},
+	)
+}
+
+// SetQuietHours sets hours the user isn't disturbed in, empty string
+// turns them off
+func (r *Repo) SetQuietHours(username string, hours string) error {
+	const q = `
+update users
+set
+	quiet_hours = :quiet_hours
+where
+	username = :username
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"username":    username,
+			"quiet_hours": sql.NullString{String: hours, Valid: hours != ""},
+		},
+	)
+}
+
+// SetReminderTier remembers the last tier of reminders fired about busy
+// stands, tiers never go back until the stand is claimed again
+func (r *Repo) SetReminderTier(names []string, tier int) error {
+	const q = `
+update stands
+set
+	reminder_tier = greatest (reminder_tier, :tier)
+where
+	name = any (:names)
+	and released = false
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"names": names,
+			"tier":  tier,
+		},
+	)
+}
+
+// ScheduleRelease sets time busy stands are released automatically at,
+// already scheduled ones keep their time
+func (r *Repo) ScheduleRelease(names []string, at time.Time) error {
+	const q = `
+update stands
+set
+	release_at = :release_at
+where
+	name = any (:names)
+	and released = false
+	and release_at is null
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"names":      names,
+			"release_at": at,
+		},
+	)
+}
+
+// KeepStand cancels automatic release of the stand, only owner of the
+// stand may do it. Reminders are paused till given time and start over
+// from the first tier, so the release warning fires again after that
+func (r *Repo) KeepStand(stand entity.Stand, until time.Time) error {
+	const q = `
+update stands
+set
+	release_at = null,
+	reminder_tier = 0,
+	reminder_ack_until = :until
+where
+	name = :name
+	and owner_username = :owner_username
+	and released = false
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"name":           stand.Name,
+			"owner_username": stand.OwnerUsername.String,
+			"until":          until,
+		},
+	)
+}
+
+// AutoReleaseStand releases the stand if it's still held by the same
+// owner and its release time has come, false means owner kept the stand
+// or it was released meanwhile
+func (r *Repo) AutoReleaseStand(stand entity.Stand, now time.Time) (bool, error) {
+	const q = `
+update stands
+set
+	owner_username = null,
+	released = true,
+	reminder_ack_until = null,
+	reminder_tier = 0,
+	release_at = null
+where
+	name = :name
+	and owner_username = :owner_username
+	and released = false
+	and release_at <= :now
+	`
+
+	res, err := r.db.NamedExec(q, map[string]any{
+		"name":           stand.Name,
+		"owner_username": stand.OwnerUsername.String,
+		"now":            now,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to auto release stand: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to auto release stand: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// AckReminder suppresses reminders about the stand until given time,
+// only owner of the stand may do it
+func (r *Repo) AckReminder(stand entity.Stand, until time.Time) error {
+	const q = `
+update stands
+set
+	reminder_ack_until = :until
+where
+	name = :name
+	and released = false
+	and owner_username = :owner_username
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"owner_username": stand.OwnerUsername.String,
+			"name":           stand.Name,
+			"until":          until,
+		},
+	)
+}
+
+func (r *Repo) CreateTransfer(transfer entity.Transfer) (int64, error) {
+	const q = `
+insert into
+	transfers (stand_name, from_username, to_username, status, created)
+values
+	(:stand_name, :from_username, :to_username, 'pending', now ()) returning id
+	`
+
+	var id int64
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&id,
+		map[string]any{
+			"stand_name":    transfer.StandName,
+			"from_username": transfer.FromUsername,
+			"to_username":   transfer.ToUsername,
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *Repo) Transfer(id int64) (entity.Transfer, error) {
+	const q = `
+select
+	id,
+	stand_name,
+	from_username,
+	to_username,
+	status,
+	created,
+	resolved
+from
+	transfers
+where
+	id = :id
+	`
+
+	var transfer entity.Transfer
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&transfer,
+		map[string]any{
+			"id": id,
+		},
+	)
+	if err != nil {
+		return entity.Transfer{}, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// AcceptTransfer hands the stand over to the recipient in one transaction,
+// the stand stays busy all the time
+func (r *Repo) AcceptTransfer(transfer entity.Transfer) error {
+	const (
+		qStand = `
+update stands
+set
+	owner_username = :to_username,
+	time_claimed = now (),
+	reminder_ack_until = null,
+	reminder_tier = 0,
+	release_at = null
+where
+	name = :stand_name
+	and released = false
+	and owner_username = :from_username
+	`
+		qTransfer = `
+update transfers
+set
+	status = 'accepted',
+	resolved = now ()
+where
+	id = :id
+	and status = 'pending'
+	`
+	)
+
+	args := map[string]any{
+		"id":            transfer.ID,
+		"stand_name":    transfer.StandName,
+		"from_username": transfer.FromUsername,
+		"to_username":   transfer.ToUsername,
+	}
+
+	return dbutils.WithTx(r.db, func(tx *sqlx.Tx) error {
+		res, err := tx.NamedExec(qTransfer, args)
+		if err != nil {
+			return fmt.Errorf("failed to resolve transfer: %w", err)
+		}
+
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("transfer %d isn't pending", transfer.ID)
+		}
+
+		res, err = tx.NamedExec(qStand, args)
+		if err != nil {
+			return fmt.Errorf("failed to transfer stand: %w", err)
+		}
+
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return ErrStandNotOwned
+		}
+
+		return nil
+	})
+}
+
+func (r *Repo) DeclineTransfer(id int64) error {
+	const q = `
+update transfers
+set
+	status = 'declined',
+	resolved = now ()
+where
+	id = :id
+	and status = 'pending'
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"id": id,
+		},
+	)
+}
+
+// ForceReleaseStand releases the stand no matter who owns it and
+// returns the previous owner, sql.ErrNoRows means stand isn't busy
+func (r *Repo) ForceReleaseStand(name string) (string, error) {
+	const q = `
+with
+	prev as (
+		select
+			name,
+			owner_username
+		from
+			stands
+		where
+			name = :name
+			and released = false
+		for update
+	)
+update stands
+set
+	owner_username = null,
+	released = true,
+	reminder_ack_until = null,
+	reminder_tier = 0,
+	release_at = null
+from
+	prev
+where
+	stands.name = prev.name
+returning
+	coalesce(prev.owner_username, '')
+	`
+
+	var owner string
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&owner,
+		map[string]any{
+			"name": name,
+		},
+	)
+	if err == sql.ErrNoRows {
+		return "", err
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to force release stand: %w", err)
+	}
+
+	return owner, nil
+}
+
+func (r *Repo) AddStandEvent(event entity.StandEvent) error {
+	const q = `
+insert into
+	stand_events (
+		stand_name,
+		kind,
+		actor_username,
+		owner_username,
+		reason,
+		created
+	)
+values
+	(
+		:stand_name,
+		:kind,
+		:actor_username,
+		:owner_username,
+		:reason,
+		now ()
+	)
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"stand_name":     event.StandName,
+			"kind":           event.Kind,
+			"actor_username": event.ActorUsername,
+			"owner_username": event.OwnerUsername,
+			"reason":         event.Reason,
+		},
+	)
+}
+
+// Role returns role assigned to the user in the chat or default role of
+// the chat, empty string means neither is set
+func (r *Repo) Role(chatID int64, username string) (string, error) {
+	const q = `
+select
+	coalesce(
+		(
+			select
+				role
+			from
+				chat_roles
+			where
+				chat_id = :chat_id
+				and username = :username
+		),
+		(
+			select
+				default_role
+			from
+				chat_settings
+			where
+				chat_id = :chat_id
+		),
+		''
+	) as role
+	`
+
+	var role string
+	err := dbutils.NamedGet(
+		r.db,
+		q,
+		&role,
+		map[string]any{
+			"chat_id":  chatID,
+			"username": username,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get role: %w", err)
+	}
+
+	return role, nil
+}
+
+func (r *Repo) Roles(chatID int64) ([]entity.ChatRole, error) {
+	const q = `
+select
+	chat_id,
+	username,
+	role
+from
+	chat_roles
+where
+	chat_id = :chat_id
+order by
+	username asc
+	`
+
+	var roles []entity.ChatRole
+
+	err := dbutils.NamedSelect(
+		r.db,
+		q,
+		&roles,
+		map[string]any{
+			"chat_id": chatID,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+func (r *Repo) SetRole(role entity.ChatRole) error {
+	const q = `
+insert into
+	chat_roles (chat_id, username, role)
+values
+	(:chat_id, :username, :role) on conflict (chat_id, username) do update
+set
+	role = excluded.role
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id":  role.ChatID,
+			"username": role.Username,
+			"role":     role.Role,
+		},
+	)
+}
+
+func (r *Repo) SetDefaultRole(chatID int64, role string) error {
+	const q = `
+insert into
+	chat_settings (chat_id, default_role)
+values
+	(:chat_id, :role) on conflict (chat_id) do update
+set
+	default_role = excluded.default_role
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"chat_id": chatID,
+			"role":    role,
+		},
+	)
+}
+
+// StandAllowlist returns users allowed to claim the stand,
+// empty list means anyone may claim it
+func (r *Repo) StandAllowlist(standName string) ([]string, error) {
+	const q = `
+select
+	username
+from
+	stand_allowlist
+where
+	stand_name = :stand_name
+order by
+	username asc
+	`
+
+	var usernames []string
+
+	err := dbutils.NamedSelect(
+		r.db,
+		q,
+		&usernames,
+		map[string]any{
+			"stand_name": standName,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stand allowlist: %w", err)
+	}
+
+	return usernames, nil
+}
+
+func (r *Repo) Allowlists() ([]entity.StandAccess, error) {
+	const q = `
+select
+	stand_name,
+	username
+from
+	stand_allowlist
+order by
+	stand_name asc,
+	username asc
+	`
+
+	var access []entity.StandAccess
+
+	err := dbutils.NamedSelect(
+		r.db,
+		q,
+		&access,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowlists: %w", err)
+	}
+
+	return access, nil
+}
+
+func (r *Repo) AllowStand(access entity.StandAccess) error {
+	const q = `
+insert into
+	stand_allowlist (stand_name, username)
+values
+	(:stand_name, :username) on conflict do nothing
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"stand_name": access.StandName,
+			"username":   access.Username,
+		},
+	)
+}
+
+func (r *Repo) DisallowStand(access entity.StandAccess) error {
+	const q = `
+delete from stand_allowlist
+where
+	stand_name = :stand_name
+	and username = :username
+	`
+
+	return dbutils.NamedExec(
+		r.db,
+		q,
+		map[string]any{
+			"stand_name": access.StandName,
+			"username":   access.Username,
+		},
+	)
 }
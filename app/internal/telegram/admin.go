@@ -0,0 +1,132 @@
+package telegram
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+// ForceRelease lets chat administrators free a stand of someone who is
+// away with `/force_release dev <reason>`, the previous owner is notified.
+// Admin role given by /roles isn't enough, telegram has to confirm sender
+// administers the chat
+func (h *Handler) ForceRelease(c telebot.Context) error {
+	isAdmin, err := h.isChatAdmin(c.Chat(), c.Sender())
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		if c.Callback() != nil {
+			return h.toast(c, h.t(c, ErrAdminsOnly))
+		}
+		return c.Reply(h.t(c, ErrAdminsOnly))
+	}
+
+	var standName, reason string
+
+	if c.Callback() != nil {
+		standName, reason = callbackFrom(c).arg(0), callbackFrom(c).arg(1)
+	} else {
+		stands, err := h.checkStands(c)
+		if err != nil {
+			return err
+		}
+
+		standName, reason = splitStandName(stands, c.Message().Payload)
+		if standName == "" || reason == "" {
+			return c.Reply(h.t(c, ErrForceReleaseUsage))
+		}
+
+		if h.needsConfirm(c, "force_release") {
+			question := h.tpl(c, TplConfirmForceRelease, tplData{
+				Stand: tplStand{Name: standName},
+				Text:  reason,
+			})
+			return h.askConfirm(c, false, question, "force_release", standName, reason)
+		}
+	}
+
+	owner, err := h.repo.ForceReleaseStand(standName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return h.respond(c, h.t(c, ErrStandNotBusy))
+	}
+
+	if err != nil {
+		return h.respond(c, h.tpl(c, ErrFailedToRelease, tplData{Err: err.Error()}))
+	}
+
+	admin := c.Sender().Username
+
+	log.Zap().Infof("%s force released %s from %s: %s", admin, standName, owner, reason)
+
+	err = h.repo.AddStandEvent(entity.StandEvent{
+		StandName:     standName,
+		Kind:          entity.EventForceRelease,
+		ActorUsername: sql.NullString{String: admin, Valid: true},
+		OwnerUsername: sql.NullString{String: owner, Valid: owner != ""},
+		Reason:        sql.NullString{String: reason, Valid: true},
+	})
+	if err != nil {
+		log.Zap().Errorf("failed to record force release: %v", err)
+	}
+
+	h.refreshDashboards()
+
+	data := tplData{
+		User:  admin,
+		Stand: tplStand{Name: standName, Owner: owner},
+		Text:  reason,
+	}
+
+	if owner != "" {
+		h.notifyOwner(owner, func(lang i18n.Lang) (string, *telebot.ReplyMarkup) {
+			return h.text(lang, TplForceReleasedOwner, data), nil
+		})
+	}
+
+	if c.Callback() != nil {
+		return c.Edit(h.tpl(c, TplForceReleased, data))
+	}
+
+	return c.Send(h.tpl(c, TplForceReleased, data))
+}
+
+// notifyOwner DMs user when the bot can reach them, the group message
+// mentions them anyway
+func (h *Handler) notifyOwner(username string, render func(lang i18n.Lang) (string, *telebot.ReplyMarkup)) {
+	users, err := h.repo.Users([]string{username})
+	if err != nil {
+		log.Zap().Errorf("failed to get user %s: %v", username, err)
+		return
+	}
+
+	if len(users) == 1 {
+		h.sendDirect(users[0], render)
+	}
+}
+
+// splitStandName cuts the longest known stand name off the beginning of
+// payload, so stand names may contain spaces, and returns the rest
+func splitStandName(stands []entity.Stand, payload string) (string, string) {
+	payload = strings.TrimSpace(payload)
+
+	var name string
+	for _, stand := range stands {
+		if len(stand.Name) <= len(name) || !strings.HasPrefix(payload, stand.Name) {
+			continue
+		}
+
+		rest := payload[len(stand.Name):]
+		if rest == "" || rest[0] == ' ' {
+			name = stand.Name
+		}
+	}
+
+	return name, strings.TrimSpace(payload[len(name):])
+}
@@ -0,0 +1,38 @@
+package telegram
+
+import (
+	"testing"
+
+	"github.com/tibeahx/claimer/pkg/entity"
+)
+
+func TestSplitStandName(t *testing.T) {
+	stands := []entity.Stand{
+		{Name: "dev"},
+		{Name: "dev 2"},
+		{Name: "release-stage"},
+	}
+
+	tests := []struct {
+		payload    string
+		wantName   string
+		wantReason string
+	}{
+		{payload: "dev owner is on vacation", wantName: "dev", wantReason: "owner is on vacation"},
+		{payload: "dev 2 tests are stuck", wantName: "dev 2", wantReason: "tests are stuck"},
+		{payload: "  release-stage   hotfix  ", wantName: "release-stage", wantReason: "hotfix"},
+		{payload: "dev", wantName: "dev", wantReason: ""},
+		{payload: "devops is down", wantName: "", wantReason: "devops is down"},
+		{payload: "stage broken", wantName: "", wantReason: "stage broken"},
+		{payload: "", wantName: "", wantReason: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			name, reason := splitStandName(stands, tt.payload)
+			if name != tt.wantName || reason != tt.wantReason {
+				t.Errorf("got %q, %q, want %q, %q", name, reason, tt.wantName, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package telegram
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/config"
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+// warnRelease warns owners that stands held past their hard limit are
+// released after the grace period unless they tap Keep
+func (h *Handler) warnRelease(chatID int64, tier config.ReminderTier, owners []string, stands []entity.Stand) error {
+	var (
+		lang      = h.chatLanguage(chatID)
+		releaseAt = time.Now().Add(tier.Grace)
+		names     = make([]string, 0, len(stands))
+		data      = tplData{
+			Users: owners,
+			Hours: int(tier.After.Hours()),
+			Time:  releaseAt,
+		}
+	)
+
+	for _, stand := range stands {
+		names = append(names, stand.Name)
+		data.Stands = append(data.Stands, newTplStand(stand))
+	}
+
+	_, err := h.bot.Tele().Send(
+		&telebot.Chat{ID: chatID},
+		h.text(lang, TplReleaseWarning, data),
+		h.keepMarkup(lang, stands),
+		h.standsTopic(chatID),
+	)
+	if err != nil {
+		return err
+	}
+
+	return h.repo.ScheduleRelease(names, releaseAt)
+}
+
+// AutoRelease releases stands whose owners didn't keep them in time, it's
+// called by the notifier
+func (h *Handler) AutoRelease(chatID int64) error {
+	stands, err := h.repo.Stands()
+	if err != nil {
+		return err
+	}
+
+	var (
+		now      = time.Now()
+		lang     = h.chatLanguage(chatID)
+		released = false
+	)
+
+	for _, stand := range stands {
+		if stand.Released || !stand.ReleaseAt.Valid || stand.ReleaseAt.Time.After(now) {
+			continue
+		}
+
+		ok, err := h.repo.AutoReleaseStand(stand, now)
+		if err != nil {
+			return err
+		}
+
+		// owner kept the stand or released it meanwhile
+		if !ok {
+			continue
+		}
+
+		released = true
+		owner := stand.OwnerUsername.String
+
+		log.Zap().Infof("auto released %s from %s", stand.Name, owner)
+
+		err = h.repo.AddStandEvent(entity.StandEvent{
+			StandName:     stand.Name,
+			Kind:          entity.EventAutoRelease,
+			OwnerUsername: sql.NullString{String: owner, Valid: owner != ""},
+		})
+		if err != nil {
+			log.Zap().Errorf("failed to record auto release: %v", err)
+		}
+
+		data := tplData{Stand: tplStand{Name: stand.Name, Owner: owner}}
+
+		_, err = h.bot.Tele().Send(
+			&telebot.Chat{ID: chatID},
+			h.text(lang, TplAutoReleased, data),
+			h.standsTopic(chatID),
+		)
+		if err != nil {
+			log.Zap().Errorf("failed to announce auto release of %s: %v", stand.Name, err)
+		}
+
+		h.notifyOwner(owner, func(lang i18n.Lang) (string, *telebot.ReplyMarkup) {
+			return h.text(lang, TplAutoReleasedOwner, data), nil
+		})
+	}
+
+	if released {
+		h.refreshDashboards()
+	}
+
+	return nil
+}
+
+// keepMarkup adds Keep and Release buttons for every stand about to be
+// released, Reminder checks the owner
+func (h *Handler) keepMarkup(lang i18n.Lang, stands []entity.Stand) *telebot.ReplyMarkup {
+	menu := make([][]telebot.InlineButton, 0, len(stands))
+
+	for _, stand := range stands {
+		data := tplData{Stand: newTplStand(stand)}
+
+		menu = append(menu, []telebot.InlineButton{
+			{
+				Text: h.text(lang, TplReminderKeep, data),
+				Data: h.callbacks.encode(anyone, "remind", reminderKeep, stand.Name),
+			},
+			{
+				Text: h.text(lang, TplReminderRelease, data),
+				Data: h.callbacks.encode(anyone, "remind", reminderRelease, stand.Name),
+			},
+		})
+	}
+
+	return &telebot.ReplyMarkup{InlineKeyboard: menu}
+}
@@ -11,28 +11,53 @@ import (
 
 const pollerTimeout = 10 * time.Second
 
+var allowedUpdates = []string{
+	"message",
+	"edited_message",
+	"inline_query",
+	"callback_query",
+	// joins, leaves and promotions, sent to administrators of the chat only
+	"chat_member",
+}
+
 type Bot struct {
 	tele *telebot.Bot
 }
 
+// NewBot receives updates by long polling, or with a webhook if the mode
+// is set to webhook in config
 func NewBot(cfg *config.Config) (*Bot, error) {
+	var poller telebot.Poller = &telebot.LongPoller{
+		Timeout:        pollerTimeout,
+		AllowedUpdates: allowedUpdates,
+	}
+
+	if cfg.Bot.Mode == config.ModeWebhook {
+		webhook, err := newWebhookPoller(cfg.Bot.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		poller = webhook
+	}
+
 	b, err := telebot.NewBot(telebot.Settings{
 		Verbose: cfg.Bot.Verbose,
 		Token:   cfg.Bot.Token,
-		Poller: &telebot.LongPoller{
-			Timeout: pollerTimeout,
-			AllowedUpdates: []string{
-				"message",
-				"edited_message",
-				"inline_query",
-				"callback_query",
-			},
-		},
+		Poller:  poller,
+		Client:  newSendClient(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to build bot: %w", err)
 	}
 
+	// telegram doesn't return updates by polling while a webhook is set,
+	// e.g. after switching back from webhook mode
+	if cfg.Bot.Mode != config.ModeWebhook {
+		if err := b.RemoveWebhook(); err != nil {
+			return nil, fmt.Errorf("failed to remove webhook: %w", err)
+		}
+	}
+
 	return &Bot{
 		tele: b,
 	}, nil
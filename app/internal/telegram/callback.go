@@ -0,0 +1,201 @@
+package telegram
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/telebot.v4"
+)
+
+// callback data layout: <version>|<issued>|<owner>|<action>|<arg>|<arg>...
+// issued is unix seconds and owner is telegram user id of the one who
+// requested the menu, both in base36, owner 0 means anyone may press it.
+// args are escaped so they may contain any character including the separator.
+// payloads that don't fit into telegram's 64 bytes are kept server-side
+// and referenced by a short key: <version>|<issued>|<owner>|*|<key>
+const (
+	callbackVersion   = "2"
+	callbackSep       = "|"
+	callbackRefAction = "*"
+	callbackMaxLen    = 64
+	callbackTTL       = 24 * time.Hour
+	callbackCtxKey    = "callback"
+
+	// anyone marks a menu which may be used by every chat member
+	anyone int64 = 0
+)
+
+var (
+	errCallbackMalformed = errors.New("malformed callback data")
+	errCallbackVersion   = errors.New("unsupported callback version")
+	errCallbackExpired   = errors.New("callback expired")
+)
+
+var (
+	callbackEscaper   = strings.NewReplacer("%", "%25", callbackSep, "%7C")
+	callbackUnescaper = strings.NewReplacer("%7C", callbackSep, "%25", "%")
+)
+
+type callbackData struct {
+	owner  int64
+	action string
+	args   []string
+	issued time.Time
+}
+
+// allowed reports whether user may press the button
+func (d callbackData) allowed(userID int64) bool {
+	return d.owner == anyone || d.owner == userID
+}
+
+// arg returns i-th argument or empty string if it's absent
+func (d callbackData) arg(i int) string {
+	if i < 0 || i >= len(d.args) {
+		return ""
+	}
+	return d.args[i]
+}
+
+type callbackRef struct {
+	data    string
+	expires time.Time
+}
+
+// callbackCodec encodes and decodes inline button data, keeping long
+// payloads in memory until they expire
+type callbackCodec struct {
+	mu   sync.Mutex
+	refs map[string]callbackRef
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func newCallbackCodec(ttl time.Duration) *callbackCodec {
+	return &callbackCodec{
+		refs: make(map[string]callbackRef),
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+func (cc *callbackCodec) encode(owner int64, action string, args ...string) string {
+	now := cc.now()
+	head := callbackVersion + callbackSep +
+		strconv.FormatInt(now.Unix(), 36) + callbackSep +
+		strconv.FormatInt(owner, 36) + callbackSep
+
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, callbackEscaper.Replace(action))
+	for _, arg := range args {
+		parts = append(parts, callbackEscaper.Replace(arg))
+	}
+
+	body := strings.Join(parts, callbackSep)
+	if len(head)+len(body) <= callbackMaxLen {
+		return head + body
+	}
+
+	return head + callbackRefAction + callbackSep + cc.store(body, now)
+}
+
+func (cc *callbackCodec) decode(raw string) (callbackData, error) {
+	parts := strings.Split(raw, callbackSep)
+	if len(parts) < 4 {
+		return callbackData{}, errCallbackMalformed
+	}
+
+	if parts[0] != callbackVersion {
+		return callbackData{}, errCallbackVersion
+	}
+
+	sec, err := strconv.ParseInt(parts[1], 36, 64)
+	if err != nil {
+		return callbackData{}, errCallbackMalformed
+	}
+
+	issued := time.Unix(sec, 0)
+	if cc.now().Sub(issued) > cc.ttl {
+		return callbackData{}, errCallbackExpired
+	}
+
+	owner, err := strconv.ParseInt(parts[2], 36, 64)
+	if err != nil {
+		return callbackData{}, errCallbackMalformed
+	}
+
+	if parts[3] == callbackRefAction {
+		if len(parts) != 5 {
+			return callbackData{}, errCallbackMalformed
+		}
+
+		body, ok := cc.load(parts[4])
+		if !ok {
+			return callbackData{}, errCallbackExpired
+		}
+
+		parts = append(parts[:3], strings.Split(body, callbackSep)...)
+	}
+
+	args := make([]string, 0, len(parts)-4)
+	for _, arg := range parts[4:] {
+		args = append(args, callbackUnescaper.Replace(arg))
+	}
+
+	return callbackData{
+		owner:  owner,
+		action: callbackUnescaper.Replace(parts[3]),
+		args:   args,
+		issued: issued,
+	}, nil
+}
+
+func (cc *callbackCodec) store(body string, now time.Time) string {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	for key, ref := range cc.refs {
+		if now.After(ref.expires) {
+			delete(cc.refs, key)
+		}
+	}
+
+	key := randomKey()
+	cc.refs[key] = callbackRef{
+		data:    body,
+		expires: now.Add(cc.ttl),
+	}
+
+	return key
+}
+
+func (cc *callbackCodec) load(key string) (string, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	ref, ok := cc.refs[key]
+	if !ok || cc.now().After(ref.expires) {
+		delete(cc.refs, key)
+		return "", false
+	}
+
+	return ref.data, true
+}
+
+func randomKey() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// callbackFrom returns decoded callback data stored by HandleCallbacks
+func callbackFrom(c telebot.Context) callbackData {
+	data, _ := c.Get(callbackCtxKey).(callbackData)
+	return data
+}
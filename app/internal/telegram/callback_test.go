@@ -0,0 +1,175 @@
+package telegram
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCallbackCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		owner  int64
+		action string
+		args   []string
+	}{
+		{name: "no args", owner: anyone, action: "list"},
+		{name: "plain args", owner: 42, action: "claim", args: []string{"dev"}},
+		{name: "separator in arg", owner: 42, action: "claim", args: []string{"dev|stage"}},
+		{name: "percent in arg", owner: 42, action: "force_release", args: []string{"dev", "100% broken"}},
+		{name: "escaped separator in arg", owner: 42, action: "claim", args: []string{"dev%7Cstage", "%25"}},
+		{name: "empty args", owner: 42, action: "confirm", args: []string{"", "", ""}},
+		{name: "negative owner", owner: -100123, action: "ping", args: []string{"alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := newCallbackCodec(callbackTTL)
+
+			raw := cc.encode(tt.owner, tt.action, tt.args...)
+			if len(raw) > callbackMaxLen {
+				t.Fatalf("encoded %d bytes, want at most %d", len(raw), callbackMaxLen)
+			}
+
+			data, err := cc.decode(raw)
+			if err != nil {
+				t.Fatalf("decode(%q) failed: %v", raw, err)
+			}
+
+			if data.owner != tt.owner || data.action != tt.action {
+				t.Errorf("got owner %d action %q, want %d %q", data.owner, data.action, tt.owner, tt.action)
+			}
+
+			if len(data.args) != len(tt.args) || (len(tt.args) > 0 && !slices.Equal(data.args, tt.args)) {
+				t.Errorf("got args %q, want %q", data.args, tt.args)
+			}
+		})
+	}
+}
+
+func TestCallbackCodecOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		ref  bool
+	}{
+		{name: "fits", args: []string{"dev"}},
+		{name: "long arg", args: []string{strings.Repeat("x", callbackMaxLen)}, ref: true},
+		{name: "many args", args: strings.Fields(strings.Repeat("stand ", 12)), ref: true},
+		{name: "escaping overflows", args: []string{strings.Repeat("|", 20)}, ref: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := newCallbackCodec(callbackTTL)
+
+			raw := cc.encode(42, "release", tt.args...)
+			if len(raw) > callbackMaxLen {
+				t.Fatalf("encoded %d bytes, want at most %d", len(raw), callbackMaxLen)
+			}
+
+			parts := strings.Split(raw, callbackSep)
+			if ref := parts[3] == callbackRefAction; ref != tt.ref {
+				t.Fatalf("ref is %t, want %t: %q", ref, tt.ref, raw)
+			}
+
+			data, err := cc.decode(raw)
+			if err != nil {
+				t.Fatalf("decode(%q) failed: %v", raw, err)
+			}
+
+			if data.action != "release" || !slices.Equal(data.args, tt.args) {
+				t.Errorf("got %q %q, want release %q", data.action, data.args, tt.args)
+			}
+		})
+	}
+}
+
+func TestCallbackCodecExpiry(t *testing.T) {
+	issued := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	long := strings.Repeat("x", callbackMaxLen)
+
+	tests := []struct {
+		name    string
+		args    []string
+		elapsed time.Duration
+		wantErr error
+	}{
+		{name: "fresh", args: []string{"dev"}, elapsed: time.Minute},
+		{name: "at ttl", args: []string{"dev"}, elapsed: callbackTTL},
+		{name: "expired", args: []string{"dev"}, elapsed: callbackTTL + time.Second, wantErr: errCallbackExpired},
+		{name: "fresh ref", args: []string{long}, elapsed: time.Minute},
+		{name: "expired ref", args: []string{long}, elapsed: callbackTTL + time.Second, wantErr: errCallbackExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := newCallbackCodec(callbackTTL)
+
+			cc.now = func() time.Time { return issued }
+			raw := cc.encode(42, "claim", tt.args...)
+
+			cc.now = func() time.Time { return issued.Add(tt.elapsed) }
+			_, err := cc.decode(raw)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCallbackCodecMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr error
+	}{
+		{name: "empty", raw: "", wantErr: errCallbackMalformed},
+		{name: "legacy", raw: "claim|dev", wantErr: errCallbackMalformed},
+		{name: "old version", raw: "1|0|0|claim", wantErr: errCallbackVersion},
+		{name: "bad owner", raw: "2|0|?|claim", wantErr: errCallbackMalformed},
+		{name: "unknown ref", raw: "2|0|0|*|deadbeef", wantErr: errCallbackExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := newCallbackCodec(callbackTTL)
+			cc.now = func() time.Time { return time.Unix(0, 0) }
+
+			if _, err := cc.decode(tt.raw); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCallbackDataAllowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		owner  int64
+		sender int64
+		want   bool
+	}{
+		{name: "owner", owner: 42, sender: 42, want: true},
+		{name: "someone else", owner: 42, sender: 7, want: false},
+		{name: "anyone", owner: anyone, sender: 7, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := newCallbackCodec(callbackTTL)
+
+			data, err := cc.decode(cc.encode(tt.owner, "claim", "dev"))
+			if err != nil {
+				t.Fatalf("decode failed: %v", err)
+			}
+
+			if got := data.allowed(tt.sender); got != tt.want {
+				t.Errorf("allowed(%d) = %t, want %t", tt.sender, got, tt.want)
+			}
+		})
+	}
+}
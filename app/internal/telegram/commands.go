@@ -0,0 +1,178 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"gopkg.in/telebot.v4"
+)
+
+// commandScope is a set of command menus a command is shown in
+type commandScope int
+
+const (
+	scopeGroup commandScope = 1 << iota
+	scopePrivate
+	// group administrators, they see group commands as well
+	scopeAdmins
+
+	scopeAll = scopeGroup | scopePrivate
+)
+
+// command describes a bot command, the registry is the only place
+// handlers, command menus, required roles and /help come from
+type command struct {
+	name string
+	// usage shown in /help, e.g. "<stand> @user"
+	args        string
+	description i18n.Key
+	// minimal role to run the command or press its buttons
+	role    string
+	handler telebot.HandlerFunc
+	// command menus the command is shown in, zero hides it
+	scope commandScope
+	// buttons with the command's action are served by its handler
+	callback bool
+	// there is no command, only buttons, e.g. reminders
+	buttonsOnly bool
+}
+
+func (h *Handler) commands() []command {
+	return []command{
+		{name: "start", description: CmdStart, role: entity.RoleViewer, handler: h.Start, scope: scopePrivate},
+		{name: "help", description: CmdHelp, role: entity.RoleViewer, handler: h.Help, scope: scopeAll},
+		{name: "list", description: CmdList, role: entity.RoleViewer, handler: h.ListStands, scope: scopeAll},
+		{name: "mystands", description: CmdMyStands, role: entity.RoleViewer, handler: h.MyStands, scope: scopeAll},
+		{name: "claim", description: CmdClaim, role: entity.RoleMember, handler: h.Claim, scope: scopeAll, callback: true},
+		{name: "release", description: CmdRelease, role: entity.RoleMember, handler: h.Release, scope: scopeAll, callback: true},
+		{name: "transfer", args: "<stand> @user", description: CmdTransfer, role: entity.RoleMember, handler: h.Transfer, scope: scopeAll, callback: true},
+		{name: "ping", description: CmdPing, role: entity.RoleMember, handler: h.Ping, scope: scopeAll, callback: true},
+		{name: "ping_all", description: CmdPingAll, role: entity.RoleMember, handler: h.PingAll, scope: scopeGroup},
+		{name: "dashboard", description: CmdDashboard, role: entity.RoleMember, handler: h.Dashboard, scope: scopeAll},
+		{name: "features_state", description: CmdFeaturesState, role: entity.RoleViewer, handler: h.FeaturesState, scope: scopeAll},
+		{name: "notifications", description: CmdNotifications, role: entity.RoleViewer, handler: h.Notifications, scope: scopeAll, callback: true},
+		{name: "settings", description: CmdSettings, role: entity.RoleViewer, handler: h.Settings, scope: scopeAll, callback: true},
+		{name: "language", args: "[me]", description: CmdLanguage, role: entity.RoleMember, handler: h.Language, scope: scopeAll, callback: true},
+		{name: "force_release", args: "<stand> <reason>", description: CmdForceRelease, role: entity.RoleAdmin, handler: h.ForceRelease, scope: scopeAdmins},
+		{name: "roles", args: "[set|default|allow|disallow ...]", description: CmdRoles, role: entity.RoleAdmin, handler: h.Roles, scope: scopeAdmins},
+		{name: "stands_topic", args: "[off]", description: CmdStandsTopic, role: entity.RoleAdmin, handler: h.StandsTopic, scope: scopeAdmins},
+		{name: "sync_members", description: CmdSyncMembers, role: entity.RoleAdmin, handler: h.SyncMembers, scope: scopeAdmins},
+		{name: "working_hours", args: "[09:00-18:00 [mon,...] [Europe/Moscow] | off | reset]", description: CmdWorkingHours, role: entity.RoleAdmin, handler: h.WorkingHours, scope: scopeAdmins},
+		{name: "remind", role: entity.RoleMember, handler: h.Reminder, buttonsOnly: true},
+		{name: "confirm", role: entity.RoleViewer, handler: h.Confirm, buttonsOnly: true},
+	}
+}
+
+// command finds command by its name or callback action, with or
+// without the leading slash
+func (h *Handler) command(name string) (command, bool) {
+	name = strings.TrimPrefix(name, "/")
+
+	for _, cmd := range h.commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+
+	return command{}, false
+}
+
+// RegisterCommands registers handlers of all commands and sets command
+// menus for group chats, private chats and group administrators in
+// every supported language
+func (h *Handler) RegisterCommands() error {
+	tele := h.bot.Tele()
+
+	for _, cmd := range h.commands() {
+		if !cmd.buttonsOnly {
+			tele.Handle("/"+cmd.name, cmd.handler)
+		}
+	}
+
+	menus := []struct {
+		scope   telebot.CommandScope
+		include commandScope
+	}{
+		{telebot.CommandScope{Type: telebot.CommandScopeDefault}, scopeAll},
+		{telebot.CommandScope{Type: telebot.CommandScopeAllPrivateChats}, scopePrivate},
+		{telebot.CommandScope{Type: telebot.CommandScopeAllGroupChats}, scopeGroup},
+		{telebot.CommandScope{Type: telebot.CommandScopeAllChatAdmin}, scopeGroup | scopeAdmins},
+	}
+
+	for _, menu := range menus {
+		for _, lang := range i18n.Supported() {
+			commands := h.menu(lang, menu.include)
+
+			code := string(lang)
+			if lang == i18n.Default {
+				// shown to users whose language has no menu of its own
+				code = ""
+			}
+
+			if err := tele.SetCommands(commands, menu.scope, code); err != nil {
+				return fmt.Errorf("failed to set %s commands: %w", menu.scope.Type, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// menu lists commands shown in any of scopes, descriptions may be
+// overridden in config for the default language
+func (h *Handler) menu(lang i18n.Lang, scope commandScope) []telebot.Command {
+	commands := make([]telebot.Command, 0)
+
+	for _, cmd := range h.commands() {
+		if cmd.scope&scope == 0 {
+			continue
+		}
+
+		description := h.text(lang, cmd.description, nil)
+		if override, ok := h.commandDescriptions[cmd.name]; ok && lang == i18n.Default {
+			description = override
+		}
+
+		commands = append(commands, telebot.Command{
+			Text:        cmd.name,
+			Description: description,
+		})
+	}
+
+	return commands
+}
+
+// Help lists commands available to the sender in the chat
+func (h *Handler) Help(c telebot.Context) error {
+	role, err := h.role(c)
+	if err != nil {
+		return err
+	}
+
+	scope := scopeGroup | scopeAdmins
+	if c.Chat().Type == telebot.ChatPrivate {
+		scope = scopePrivate
+	}
+
+	lines := []string{h.t(c, MsgHelpTitle)}
+
+	for _, cmd := range h.commands() {
+		if cmd.scope&scope == 0 || !entity.RoleAtLeast(role, cmd.role) {
+			continue
+		}
+
+		usage := "/" + cmd.name
+		if cmd.args != "" {
+			usage += " " + cmd.args
+		}
+
+		lines = append(lines, h.tpl(c, TplHelpCommand, tplData{
+			Text:   usage,
+			Status: h.t(c, cmd.description),
+		}))
+	}
+
+	return c.Reply(strings.Join(lines, "\n"), telebot.ModeHTML)
+}
@@ -0,0 +1,103 @@
+package telegram
+
+import (
+	"slices"
+
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	confirmYes = "yes"
+	confirmNo  = "no"
+
+	confirmedCtxKey = "confirmed"
+)
+
+// needsConfirm reports whether action is configured to be confirmed and
+// the update isn't a confirmation already
+func (h *Handler) needsConfirm(c telebot.Context, action string) bool {
+	if !slices.Contains(h.confirm, action) {
+		return false
+	}
+
+	confirmed, _ := c.Get(confirmedCtxKey).(bool)
+
+	return !confirmed
+}
+
+// askConfirm asks sender whether they are sure, Yes repeats the action
+// with the same args. The question replaces the menu if inPlace is set,
+// otherwise it's sent as a separate message
+func (h *Handler) askConfirm(c telebot.Context, inPlace bool, question, action string, args ...string) error {
+	owner := c.Sender().ID
+
+	markup := &telebot.ReplyMarkup{
+		InlineKeyboard: createInlineKeyboard([]inlineButton{
+			{
+				text: h.t(c, MsgButtonYes),
+				data: h.callbacks.encode(owner, "confirm", append([]string{confirmYes, action}, args...)...),
+			},
+			{
+				text: h.t(c, MsgButtonNo),
+				data: h.callbacks.encode(owner, "confirm", confirmNo),
+			},
+		}),
+	}
+
+	if inPlace && c.Callback() != nil {
+		return c.Edit(question, markup)
+	}
+
+	return h.sendMenu(c, question, markup)
+}
+
+// Confirm handles buttons of confirmations, the confirmed action is run
+// as if its own button was pressed and answers by editing the question
+func (h *Handler) Confirm(c telebot.Context) error {
+	data := callbackFrom(c)
+
+	if data.arg(0) != confirmYes {
+		return c.Edit(h.t(c, MsgCancelled))
+	}
+
+	// yes carries the action to run and its arguments
+	if len(data.args) < 2 {
+		return h.toast(c, h.t(c, ErrButtonExpired))
+	}
+
+	action := data.arg(1)
+
+	cmd, ok := h.command(action)
+	if !ok {
+		return nil
+	}
+
+	allowed, err := h.authorize(c, "/"+action)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return c.Edit(h.tpl(c, ErrRoleRequired, tplData{Text: h.requiredRole(action)}))
+	}
+
+	c.Set(callbackCtxKey, callbackData{
+		owner:  data.owner,
+		action: action,
+		args:   data.args[2:],
+		issued: data.issued,
+	})
+	c.Set(confirmedCtxKey, true)
+
+	return cmd.handler(c)
+}
+
+// respond edits the confirmation for confirmed actions, otherwise replies
+// to the command
+func (h *Handler) respond(c telebot.Context, text string, opts ...any) error {
+	if c.Callback() != nil {
+		return c.Edit(text, opts...)
+	}
+
+	return c.Reply(text, opts...)
+}
@@ -1,33 +1,144 @@
 package telegram
 
+import "github.com/tibeahx/claimer/app/internal/i18n"
+
 const (
 	EmojiComputer = "🖥️"
 	EmojiFree     = "✅"
 	EmojiBusy     = "❌"
+	EmojiSelected = "☑️"
+)
+
+// message keys, default templates are in messages.go and may be
+// overridden in config.yaml under bot.templates
+const (
+	ErrNoEnvironments      i18n.Key = "err_no_environments"
+	ErrNoBusyStands        i18n.Key = "err_no_busy_stands"
+	ErrNoFreeStands        i18n.Key = "err_no_free_stands"
+	ErrStandBusy           i18n.Key = "err_stand_busy"
+	ErrStandNotFound       i18n.Key = "err_stand_not_found"
+	ErrNoStandsToRelease   i18n.Key = "err_no_stands_to_release"
+	ErrFailedToClaim       i18n.Key = "err_failed_to_claim"
+	ErrFailedToRelease     i18n.Key = "err_failed_to_release"
+	ErrFailedToAddUser     i18n.Key = "err_failed_to_add_user"
+	ErrFeaturesState       i18n.Key = "err_features_state"
+	ErrNoFeatures          i18n.Key = "err_no_features"
+	ErrButtonExpired       i18n.Key = "err_button_expired"
+	ErrMenuNotYours        i18n.Key = "err_menu_not_yours"
+	ErrNotStandOwner       i18n.Key = "err_not_stand_owner"
+	ErrUnknownLanguage     i18n.Key = "err_unknown_language"
+	ErrNoUsername          i18n.Key = "err_no_username"
+	ErrStartBotFirst       i18n.Key = "err_start_bot_first"
+	ErrTransferUsage       i18n.Key = "err_transfer_usage"
+	ErrTransferStale       i18n.Key = "err_transfer_stale"
+	ErrFailedToTransfer    i18n.Key = "err_failed_to_transfer"
+	ErrRoleRequired        i18n.Key = "err_role_required"
+	ErrStandNotAllowed     i18n.Key = "err_stand_not_allowed"
+	ErrRolesUsage          i18n.Key = "err_roles_usage"
+	ErrNoOwnStands         i18n.Key = "err_no_own_stands"
+	ErrNotInTopic          i18n.Key = "err_not_in_topic"
+	ErrFailedToSyncMembers i18n.Key = "err_failed_to_sync_members"
+	ErrWorkingHoursUsage   i18n.Key = "err_working_hours_usage"
+	ErrGroupOnly           i18n.Key = "err_group_only"
+	ErrForceReleaseUsage   i18n.Key = "err_force_release_usage"
+	ErrAdminsOnly          i18n.Key = "err_admins_only"
+	ErrStandNotBusy        i18n.Key = "err_stand_not_busy"
 
-	ErrNoEnvironments    = "no environments found"
-	ErrNoBusyStands      = "no busy stands found"
-	ErrNoFreeStands      = "no free stands available"
-	ErrStandBusy         = "stand is busy, choose another free one"
-	ErrStandNotFound     = "stand not found"
-	ErrNoStandsToRelease = "you have no stands to release"
-	ErrFailedToClaim     = "failed to claim stand: %v"
-	ErrFailedToRelease   = "failed to release stand: %v"
+	MsgChooseStand         i18n.Key = "msg_choose_stand"
+	MsgChooseToRelease     i18n.Key = "msg_choose_to_release"
+	MsgChooseUserToPing    i18n.Key = "msg_choose_user_to_ping"
+	MsgChooseLanguage      i18n.Key = "msg_choose_language"
+	MsgLanguageName        i18n.Key = "msg_language_name"
+	MsgStartPrivate        i18n.Key = "msg_start_private"
+	MsgChooseNotifications i18n.Key = "msg_choose_notifications"
+	MsgButtonNotifyPrivate i18n.Key = "msg_button_notify_private"
+	MsgButtonNotifyGroup   i18n.Key = "msg_button_notify_group"
+	MsgNotifyPrivateSet    i18n.Key = "msg_notify_private_set"
+	MsgButtonAccept        i18n.Key = "msg_button_accept"
+	MsgButtonDecline       i18n.Key = "msg_button_decline"
+	MsgMyStandsTitle       i18n.Key = "msg_my_stands_title"
+	MsgButtonYes           i18n.Key = "msg_button_yes"
+	MsgButtonNo            i18n.Key = "msg_button_no"
+	MsgCancelled           i18n.Key = "msg_cancelled"
+	MsgStandsTopicSet      i18n.Key = "msg_stands_topic_set"
+	MsgWorkingHoursAnyTime i18n.Key = "msg_working_hours_any_time"
+	MsgSettingsSaved       i18n.Key = "msg_settings_saved"
+	MsgQuietHoursOff       i18n.Key = "msg_quiet_hours_off"
+	MsgStandsTopicReset    i18n.Key = "msg_stands_topic_reset"
+	MsgHelpTitle           i18n.Key = "msg_help_title"
+	MsgDigestBusy          i18n.Key = "msg_digest_busy"
+	MsgNotifyGroupSet      i18n.Key = "msg_notify_group_set"
 
-	MsgChooseStand      = "сhoose stand to claim:"
-	MsgChooseToRelease  = "сhoose stand to release:"
-	MsgChooseUserToPing = "сhoose user to ping:"
+	TplStandClaimed        i18n.Key = "tpl_stand_claimed"
+	TplStandReleased       i18n.Key = "tpl_stand_released"
+	TplPingUser            i18n.Key = "tpl_ping_user"
+	TplPingAllUsers        i18n.Key = "tpl_ping_all_users"
+	TplNotify              i18n.Key = "tpl_notify"
+	TplReleaseWarning      i18n.Key = "tpl_release_warning"
+	TplReleaseKept         i18n.Key = "tpl_release_kept"
+	TplAutoReleased        i18n.Key = "tpl_auto_released"
+	TplAutoReleasedOwner   i18n.Key = "tpl_auto_released_owner"
+	TplNotifyAdmins        i18n.Key = "tpl_notify_admins"
+	TplStandBusyBy         i18n.Key = "tpl_stand_busy_by"
+	TplStandFree           i18n.Key = "tpl_stand_free"
+	TplGreetings           i18n.Key = "tpl_greetings"
+	TplStandInfo           i18n.Key = "tpl_stand_info"
+	TplButtonStand         i18n.Key = "tpl_button_stand"
+	TplButtonUser          i18n.Key = "tpl_button_user"
+	TplFeatureState        i18n.Key = "tpl_feature_state"
+	TplDashboardTitle      i18n.Key = "tpl_dashboard_title"
+	TplButtonClaim         i18n.Key = "tpl_button_claim"
+	TplButtonRelease       i18n.Key = "tpl_button_release"
+	TplChatLanguageSet     i18n.Key = "tpl_chat_language_set"
+	TplUserLanguageSet     i18n.Key = "tpl_user_language_set"
+	TplReminderRelease     i18n.Key = "tpl_reminder_release"
+	TplReminderExtend      i18n.Key = "tpl_reminder_extend"
+	TplReminderKeep        i18n.Key = "tpl_reminder_keep"
+	TplReminderSnooze      i18n.Key = "tpl_reminder_snooze"
+	TplReminderExtended    i18n.Key = "tpl_reminder_extended"
+	TplReminderSnoozed     i18n.Key = "tpl_reminder_snoozed"
+	TplTransferOffer       i18n.Key = "tpl_transfer_offer"
+	TplTransferAccepted    i18n.Key = "tpl_transfer_accepted"
+	TplTransferDeclined    i18n.Key = "tpl_transfer_declined"
+	TplForceReleased       i18n.Key = "tpl_force_released"
+	TplForceReleasedOwner  i18n.Key = "tpl_force_released_owner"
+	TplRoleSet             i18n.Key = "tpl_role_set"
+	TplDefaultRoleSet      i18n.Key = "tpl_default_role_set"
+	TplStandAllowed        i18n.Key = "tpl_stand_allowed"
+	TplStandDisallowed     i18n.Key = "tpl_stand_disallowed"
+	TplMyStand             i18n.Key = "tpl_my_stand"
+	TplConfirmRelease      i18n.Key = "tpl_confirm_release"
+	TplConfirmForceRelease i18n.Key = "tpl_confirm_force_release"
+	TplConfirmTransfer     i18n.Key = "tpl_confirm_transfer"
+	TplDigestTitle         i18n.Key = "tpl_digest_title"
+	TplWorkingHours        i18n.Key = "tpl_working_hours"
+	TplSettings            i18n.Key = "tpl_settings"
+	TplQuietHours          i18n.Key = "tpl_quiet_hours"
+	TplMembersSynced       i18n.Key = "tpl_members_synced"
+	TplDigestFree          i18n.Key = "tpl_digest_free"
+	TplHelpCommand         i18n.Key = "tpl_help_command"
+	TplStartInGroup        i18n.Key = "tpl_start_in_group"
+)
 
-	TplStandClaimed  = "@%s has claimed %s"
-	TplStandReleased = "@%s has released %s"
-	TplPingUser      = "@%s would you mind releasing your stands??"
-	TplPingAllUsers  = "%s, would you mind releasing your stands?"
-	TplStandBusyBy   = "busy by @%s for %d h. %s"
-	TplStandFree     = "is free %s"
-	TplGreetings     = "Hello @%s, I'm StandClaimer bot, I will help you to manage environments across the team. Tap `/` on the group menu to see commands"
-	TplStandInfo     = "%s %s %s"
-	TplUserStand     = "@%s: %s"
-	TplButtonStand   = "%s %s"
-	TplButtonUser    = "@%s (%s)"
-	TplFeatureState  = "feature: %s %s"
+// descriptions of commands in menus and /help
+const (
+	CmdStart         i18n.Key = "cmd_start"
+	CmdHelp          i18n.Key = "cmd_help"
+	CmdList          i18n.Key = "cmd_list"
+	CmdMyStands      i18n.Key = "cmd_mystands"
+	CmdClaim         i18n.Key = "cmd_claim"
+	CmdRelease       i18n.Key = "cmd_release"
+	CmdTransfer      i18n.Key = "cmd_transfer"
+	CmdPing          i18n.Key = "cmd_ping"
+	CmdPingAll       i18n.Key = "cmd_ping_all"
+	CmdDashboard     i18n.Key = "cmd_dashboard"
+	CmdFeaturesState i18n.Key = "cmd_features_state"
+	CmdNotifications i18n.Key = "cmd_notifications"
+	CmdLanguage      i18n.Key = "cmd_language"
+	CmdForceRelease  i18n.Key = "cmd_force_release"
+	CmdRoles         i18n.Key = "cmd_roles"
+	CmdStandsTopic   i18n.Key = "cmd_stands_topic"
+	CmdSyncMembers   i18n.Key = "cmd_sync_members"
+	CmdWorkingHours  i18n.Key = "cmd_working_hours"
+	CmdSettings      i18n.Key = "cmd_settings"
 )
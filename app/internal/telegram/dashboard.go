@@ -0,0 +1,165 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+// originDashboard is passed as the last callback argument of dashboard
+// buttons, so handlers answer with a toast instead of editing the message
+const originDashboard = "dashboard"
+
+// Dashboard sends stands overview to the chat and pins it, the message
+// is kept up to date on every claim/release and by RefreshDashboards
+func (h *Handler) Dashboard(c telebot.Context) error {
+	stands, err := h.checkStands(c)
+	if err != nil {
+		return err
+	}
+
+	text, markup := h.renderDashboard(h.chatLanguage(c.Chat().ID), stands)
+
+	topic := h.standsTopic(c.Chat().ID)
+	if topic.ThreadID == 0 {
+		topic = messageTopic(c.Message())
+	}
+
+	msg, err := h.bot.Tele().Send(c.Chat(), text, markup, topic, telebot.ModeHTML)
+	if err != nil {
+		return fmt.Errorf("failed to send dashboard: %w", err)
+	}
+
+	prev, found, err := h.repo.Dashboard(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		if err := h.bot.Tele().Unpin(c.Chat(), prev.MessageID); err != nil {
+			log.Zap().Warnf("failed to unpin previous dashboard: %v", err)
+		}
+	}
+
+	if err := h.bot.Tele().Pin(msg, telebot.Silent); err != nil {
+		log.Zap().Warnf("failed to pin dashboard: %v", err)
+	}
+
+	return h.repo.SaveDashboard(entity.Dashboard{
+		ChatID:    c.Chat().ID,
+		MessageID: msg.ID,
+	})
+}
+
+// RefreshDashboards edits every known dashboard so durations stay current
+func (h *Handler) RefreshDashboards() error {
+	dashboards, err := h.repo.Dashboards()
+	if err != nil {
+		return err
+	}
+
+	if len(dashboards) == 0 {
+		return nil
+	}
+
+	stands, err := h.repo.Stands()
+	if err != nil {
+		return err
+	}
+
+	for _, dashboard := range dashboards {
+		if err := h.editDashboard(dashboard, stands); err != nil {
+			log.Zap().Errorf("failed to refresh dashboard in chat %d: %v", dashboard.ChatID, err)
+		}
+	}
+
+	return nil
+}
+
+// refreshDashboards is called after claim/release, stands are shared by
+// all chats so every dashboard is updated, failures are only logged since
+// the dashboard is secondary to the action
+func (h *Handler) refreshDashboards() {
+	if err := h.RefreshDashboards(); err != nil {
+		log.Zap().Errorf("failed to refresh dashboards: %v", err)
+	}
+}
+
+// refreshDashboard updates dashboard of the chat if there is one
+func (h *Handler) refreshDashboard(chatID int64) {
+	dashboard, found, err := h.repo.Dashboard(chatID)
+	if err != nil {
+		log.Zap().Errorf("failed to get dashboard: %v", err)
+		return
+	}
+
+	if !found {
+		return
+	}
+
+	stands, err := h.repo.Stands()
+	if err != nil {
+		log.Zap().Errorf("failed to get stands: %v", err)
+		return
+	}
+
+	if err := h.editDashboard(dashboard, stands); err != nil {
+		log.Zap().Errorf("failed to refresh dashboard in chat %d: %v", chatID, err)
+	}
+}
+
+func (h *Handler) editDashboard(dashboard entity.Dashboard, stands []entity.Stand) error {
+	text, markup := h.renderDashboard(h.chatLanguage(dashboard.ChatID), stands)
+
+	msg := telebot.StoredMessage{
+		MessageID: strconv.Itoa(dashboard.MessageID),
+		ChatID:    dashboard.ChatID,
+	}
+
+	_, err := h.bot.Tele().Edit(msg, text, markup, telebot.ModeHTML)
+	if errors.Is(err, telebot.ErrSameMessageContent) || errors.Is(err, telebot.ErrMessageNotModified) {
+		return nil
+	}
+
+	return err
+}
+
+func (h *Handler) renderDashboard(lang i18n.Lang, stands []entity.Stand) (string, *telebot.ReplyMarkup) {
+	lines := append(
+		[]string{h.text(lang, TplDashboardTitle, tplData{Time: time.Now()})},
+		h.renderStandList(lang, stands)...,
+	)
+
+	menu := make([][]telebot.InlineButton, 0, len(stands))
+
+	for _, stand := range stands {
+		if stand.Name == "" {
+			continue
+		}
+
+		btn := telebot.InlineButton{
+			Text: h.text(lang, TplButtonClaim, tplData{Stand: newTplStand(stand)}),
+			Data: h.callbacks.encode(anyone, "claim", stand.Name, originDashboard),
+		}
+
+		if !stand.Released {
+			btn = telebot.InlineButton{
+				Text: h.text(lang, TplButtonRelease, tplData{Stand: newTplStand(stand)}),
+				Data: h.callbacks.encode(anyone, "release", stand.Name, originDashboard),
+			}
+		}
+
+		menu = append(menu, []telebot.InlineButton{btn})
+	}
+
+	return strings.Join(lines, "\n"), &telebot.ReplyMarkup{
+		InlineKeyboard: menu,
+	}
+}
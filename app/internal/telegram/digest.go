@@ -0,0 +1,62 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tibeahx/claimer/pkg/entity"
+	"gopkg.in/telebot.v4"
+)
+
+// Digest posts busy stands with owners and durations and the free ones
+// to the chat, it's sent by the scheduled digest worker
+func (h *Handler) Digest(chatID int64) error {
+	if chatID == 0 {
+		return nil
+	}
+
+	stands, err := h.repo.Stands()
+	if err != nil {
+		return err
+	}
+
+	var (
+		lang = h.chatLanguage(chatID)
+		busy = make([]entity.Stand, 0, len(stands))
+		free = make([]tplStand, 0, len(stands))
+	)
+
+	for _, stand := range stands {
+		switch {
+		case stand.Name == "":
+		case stand.Released:
+			free = append(free, newTplStand(stand))
+		default:
+			busy = append(busy, stand)
+		}
+	}
+
+	lines := []string{h.text(lang, TplDigestTitle, tplData{Time: time.Now()})}
+
+	if len(busy) > 0 {
+		lines = append(lines, h.text(lang, MsgDigestBusy, nil))
+		lines = append(lines, h.renderStandList(lang, busy)...)
+	}
+
+	if len(free) > 0 {
+		lines = append(lines, h.text(lang, TplDigestFree, tplData{Stands: free}))
+	}
+
+	_, err = h.bot.Tele().Send(
+		&telebot.Chat{ID: chatID},
+		strings.Join(lines, "\n"),
+		h.standsTopic(chatID),
+		telebot.ModeHTML,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+
+	return nil
+}
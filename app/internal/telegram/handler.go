@@ -3,24 +3,97 @@ package telegram
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
+	"github.com/tibeahx/claimer/app/internal/config"
 	gitlabwrapper "github.com/tibeahx/claimer/app/internal/gitlab"
+	"github.com/tibeahx/claimer/app/internal/i18n"
 	"github.com/tibeahx/claimer/app/internal/repo"
+	"github.com/tibeahx/claimer/app/internal/workhours"
 	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
 	"gopkg.in/telebot.v4"
 )
 
-type notifierFunc func(chatID int64, users ...string) error
+type notifierFunc func(chatID int64, tier config.ReminderTier, stands ...entity.Stand) error
 
-const ctxTimeout = 2 * time.Second
+const (
+	ctxTimeout      = 2 * time.Second
+	respondedCtxKey = "responded"
+)
 
 type Handler struct {
 	repo          *repo.Repo
 	bot           *Bot
 	gitlabWrapper *gitlabwrapper.GitlabClientWrapper
+	callbacks     *callbackCodec
+	memberRoles   *memberRoles
+	languages     *languages
+	messages      *i18n.Templates
+	defaultRole   string
+	menuTTL       time.Duration
+	// actions asked to be confirmed before they are done
+	confirm []string
+	// command name -> description overriding the default one in menus
+	commandDescriptions map[string]string
+	// working hours of chats that have none of their own
+	workingHours workhours.Hours
+	// only working hours count in time stands are held for
+	countHeld bool
+	// group chat workers post to, see TeamChatID
+	teamChatID atomic.Int64
+}
+
+type handlerOptions func(*Handler)
+
+// WithDefaultRole sets role of users the chat has no role for
+func WithDefaultRole(role string) handlerOptions {
+	return func(h *Handler) {
+		h.defaultRole = role
+	}
+}
+
+// WithConfirm makes actions, e.g. release or transfer, ask for
+// confirmation first
+func WithConfirm(actions ...string) handlerOptions {
+	return func(h *Handler) {
+		h.confirm = actions
+	}
+}
+
+// WithCommandDescriptions overrides descriptions of commands in menus,
+// keys are command names with or without the leading slash
+func WithCommandDescriptions(descriptions map[string]string) handlerOptions {
+	return func(h *Handler) {
+		for name, description := range descriptions {
+			h.commandDescriptions[strings.TrimPrefix(name, "/")] = description
+		}
+	}
+}
+
+// WithMenuTTL sets how long keyboards sent in reply to commands live,
+// zero keeps the default
+func WithMenuTTL(ttl time.Duration) handlerOptions {
+	return func(h *Handler) {
+		if ttl > 0 {
+			h.menuTTL = ttl
+		}
+	}
+}
+
+// WithWorkingHours sets working hours of chats that have none of their
+// own, countHeld makes only working hours count in time stands are held
+// for when reminder thresholds are checked
+func WithWorkingHours(hours workhours.Hours, countHeld bool) handlerOptions {
+	return func(h *Handler) {
+		h.workingHours = hours
+		h.countHeld = countHeld
+	}
 }
 
 type inlineButton struct {
@@ -32,58 +105,227 @@ func NewHandler(
 	b *Bot,
 	repo *repo.Repo,
 	gitlabWrapper *gitlabwrapper.GitlabClientWrapper,
+	messages *i18n.Templates,
+	opts ...handlerOptions,
 ) *Handler {
-	return &Handler{
+	h := &Handler{
 		repo:          repo,
 		bot:           b,
 		gitlabWrapper: gitlabWrapper,
+		callbacks:     newCallbackCodec(callbackTTL),
+		memberRoles:   newMemberRoles(memberRoleTTL),
+		languages:     newLanguages(languageTTL),
+		messages:      messages,
+		defaultRole:   entity.RoleMember,
+		menuTTL:       defaultMenuTTL,
+
+		commandDescriptions: make(map[string]string),
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 func (h *Handler) HandleCallbacks(c telebot.Context) error {
-	data := strings.Split(c.Callback().Data, ":")
-	if len(data) != 2 {
+	data, err := h.callbacks.decode(c.Callback().Data)
+	if err != nil {
+		if errors.Is(err, errCallbackExpired) || errors.Is(err, errCallbackVersion) {
+			return c.Respond(&telebot.CallbackResponse{Text: h.t(c, ErrButtonExpired)})
+		}
 		return c.Respond()
 	}
 
-	action, standName := data[0], data[1]
+	if !data.allowed(c.Sender().ID) {
+		return c.Respond(&telebot.CallbackResponse{Text: h.t(c, ErrMenuNotYours)})
+	}
+
+	allowed, err := h.authorize(c, "/"+data.action)
+	if err != nil {
+		return err
+	}
 
-	c.Message().Payload = standName
+	if !allowed {
+		return c.Respond(&telebot.CallbackResponse{
+			Text: h.tpl(c, ErrRoleRequired, tplData{Text: h.requiredRole(data.action)}),
+		})
+	}
 
-	handlers := h.CallbackHandlers()
+	c.Set(callbackCtxKey, data)
 
-	if h, ok := handlers["/"+action]; ok {
-		err := h(c)
+	if cmd, ok := h.command(data.action); ok && (cmd.callback || cmd.buttonsOnly) {
+		err := cmd.handler(c)
 		if err != nil {
 			return err
 		}
+		// toasts leave the menu as is, otherwise it shows the result now
+		if responded, _ := c.Get(respondedCtxKey).(bool); responded {
+			return nil
+		}
+		h.closeMenu(c)
 		return c.Respond()
 	}
 
 	return c.Respond()
 }
 
-func (h *Handler) Notify(chatID int64) notifierFunc {
-	return func(chatID int64, users ...string) error {
-		if len(users) == 0 {
+// Notify reminds about stands held for longer than the tier allows,
+// depending on the tier owners are reminded as they chose, mentioned in
+// the group, chat admins are called or owners are warned the stands are
+// about to be released. Every reminder carries buttons to
+// release the stand, extend it or snooze reminders till tomorrow. Stands
+// remember the tier, so each tier fires once per claim
+func (h *Handler) Notify() notifierFunc {
+	return func(chatID int64, tier config.ReminderTier, stands ...entity.Stand) error {
+		if len(stands) == 0 {
 			return nil
 		}
 
-		mentionsFormatted := make([]string, 0, len(users))
+		var (
+			usernames = make([]string, 0, len(stands))
+			owned     = make(map[string][]entity.Stand, len(stands))
+		)
 
+		for _, stand := range stands {
+			owner := stand.OwnerUsername.String
+			if _, ok := owned[owner]; !ok {
+				usernames = append(usernames, owner)
+			}
+			owned[owner] = append(owned[owner], stand)
+		}
+
+		users, err := h.repo.Users(usernames)
+		if err != nil {
+			return err
+		}
+
+		private := make(map[string]entity.User, len(users))
 		for _, user := range users {
-			mentionsFormatted = append(mentionsFormatted, "@"+user)
+			private[user.Username] = user
 		}
 
-		message := fmt.Sprintf(
-			"%s, would you mind to release the stand? It's been busy for more than 100 hours",
-			strings.Join(mentionsFormatted, ", "),
+		var (
+			now          = time.Now()
+			hours        = int(tier.After.Hours())
+			delivered    = make([]entity.Stand, 0, len(stands))
+			public       = make([]string, 0, len(usernames))
+			publicStands = make([]entity.Stand, 0, len(stands))
 		)
 
-		_, err := h.bot.Tele().Send(&telebot.Chat{ID: chatID}, message)
+		for _, username := range usernames {
+			user, userStands := private[username], owned[username]
 
-		return err
+			// quiet hours are respected by every tier
+			if h.quiet(user, now) {
+				continue
+			}
+
+			if tier.Notify == config.NotifyOwner {
+				sent := h.sendPrivate(user, func(lang i18n.Lang) (string, *telebot.ReplyMarkup) {
+					return h.text(lang, TplNotify, tplData{
+						Users: []string{username},
+						Hours: hours,
+					}), h.reminderMarkup(lang, userStands)
+				})
+				if sent {
+					delivered = append(delivered, userStands...)
+					continue
+				}
+			}
+
+			public = append(public, username)
+			publicStands = append(publicStands, userStands...)
+		}
+
+		if len(public) > 0 {
+			notify := h.notifyGroup
+			if tier.Notify == config.NotifyRelease {
+				notify = h.warnRelease
+			}
+
+			if err := notify(chatID, tier, public, publicStands); err != nil {
+				return err
+			}
+			delivered = append(delivered, publicStands...)
+		}
+
+		if len(delivered) > 0 {
+			names := make([]string, 0, len(delivered))
+			for _, stand := range delivered {
+				names = append(names, stand.Name)
+			}
+
+			if err := h.repo.SetReminderTier(names, tier.Level); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// notifyGroup mentions owners of the stands in the group, admins tier
+// mentions chat admins too
+func (h *Handler) notifyGroup(chatID int64, tier config.ReminderTier, owners []string, stands []entity.Stand) error {
+	var (
+		lang = h.chatLanguage(chatID)
+		key  = TplNotify
+		data = tplData{
+			Users: owners,
+			Hours: int(tier.After.Hours()),
+		}
+	)
+
+	if tier.Notify == config.NotifyAdmins {
+		if admins := h.chatAdmins(chatID); len(admins) > 0 {
+			key = TplNotifyAdmins
+			data.Users = admins
+			for _, stand := range stands {
+				data.Stands = append(data.Stands, newTplStand(stand))
+			}
+		}
+	}
+
+	_, err := h.bot.Tele().Send(
+		&telebot.Chat{ID: chatID},
+		h.text(lang, key, data),
+		h.reminderMarkup(lang, stands),
+		h.standsTopic(chatID),
+	)
+
+	return err
+}
+
+// chatAdmins returns usernames of telegram administrators of the chat and
+// of users with admin role in it
+func (h *Handler) chatAdmins(chatID int64) []string {
+	admins := make([]string, 0)
+
+	members, err := h.bot.Tele().AdminsOf(&telebot.Chat{ID: chatID})
+	if err != nil {
+		log.Zap().Errorf("failed to get admins of chat %d: %v", chatID, err)
+	}
+
+	for _, member := range members {
+		if member.User != nil && !member.User.IsBot && member.User.Username != "" {
+			admins = append(admins, member.User.Username)
+		}
 	}
+
+	roles, err := h.repo.Roles(chatID)
+	if err != nil {
+		log.Zap().Errorf("failed to get roles of chat %d: %v", chatID, err)
+	}
+
+	for _, role := range roles {
+		if role.Role == entity.RoleAdmin && !slices.Contains(admins, role.Username) {
+			admins = append(admins, role.Username)
+		}
+	}
+
+	return admins
 }
 
 func (h *Handler) PingAll(c telebot.Context) error {
@@ -92,26 +334,22 @@ func (h *Handler) PingAll(c telebot.Context) error {
 		return err
 	}
 
-	var (
-		mentions = make(map[string]string)
-		parts    = make([]string, 0, len(stands))
-	)
+	busy := make([]tplStand, 0, len(stands))
 
 	for _, stand := range stands {
 		if stand.Released {
 			continue
 		}
 		if stand.OwnerUsername.String != "" && stand.Name != "" {
-			mentions[stand.OwnerUsername.String] = stand.Name
-			parts = append(parts, fmt.Sprintf(TplUserStand, stand.OwnerUsername.String, stand.Name))
+			busy = append(busy, newTplStand(stand))
 		}
 	}
 
-	if len(mentions) == 0 {
-		return c.Reply(ErrNoBusyStands)
+	if len(busy) == 0 {
+		return c.Reply(h.t(c, ErrNoBusyStands))
 	}
 
-	message := fmt.Sprintf(TplPingAllUsers, strings.Join(parts, ", "))
+	message := h.tpl(c, TplPingAllUsers, tplData{Stands: busy})
 	return c.Send(message)
 }
 
@@ -122,14 +360,14 @@ func (h *Handler) Ping(c telebot.Context) error {
 	}
 
 	if c.Callback() != nil {
-		username := c.Message().Payload
+		username := callbackFrom(c).arg(0)
 
 		for _, stand := range stands {
 			if stand.OwnerUsername.String == username && !stand.Released {
-				return c.Edit(fmt.Sprintf(TplPingUser, username))
+				return c.Edit(h.tpl(c, TplPingUser, tplData{User: username}))
 			}
 		}
-		return c.Edit(ErrNoBusyStands)
+		return c.Edit(h.t(c, ErrNoBusyStands))
 	}
 
 	buttons := make([]inlineButton, 0, len(stands))
@@ -143,18 +381,18 @@ func (h *Handler) Ping(c telebot.Context) error {
 		if _, exists := usersToPing[stand.OwnerUsername.String]; !exists {
 			usersToPing[stand.OwnerUsername.String] = struct{}{}
 			buttons = append(buttons, inlineButton{
-				text: fmt.Sprintf(TplButtonUser, stand.OwnerUsername.String, stand.Name),
-				data: fmt.Sprintf("ping:%s", stand.OwnerUsername.String),
+				text: h.tpl(c, TplButtonUser, tplData{Stand: newTplStand(stand)}),
+				data: h.callbacks.encode(c.Sender().ID, "ping", stand.OwnerUsername.String),
 			})
 		}
 	}
 
 	if len(buttons) == 0 {
-		return c.Reply(ErrNoBusyStands)
+		return c.Reply(h.t(c, ErrNoBusyStands))
 	}
 
 	menu := createInlineKeyboard(buttons)
-	return c.Reply(MsgChooseUserToPing, &telebot.ReplyMarkup{
+	return h.sendMenu(c, h.t(c, MsgChooseUserToPing), &telebot.ReplyMarkup{
 		InlineKeyboard: menu,
 	})
 }
@@ -165,29 +403,15 @@ func (h *Handler) ListStands(c telebot.Context) error {
 		return err
 	}
 
-	standInfos := make([]string, 0, len(stands))
-
-	for _, stand := range stands {
-		if stand.Name == "" {
-			continue
-		}
-
-		standInfo := fmt.Sprintf(TplStandInfo,
-			EmojiComputer,
-			stand.Name,
-			formatStandStatus(stand),
-		)
-
-		standInfos = append(standInfos, standInfo)
-	}
+	standInfos := h.renderStandList(h.lang(c), stands)
 
 	if len(standInfos) == 0 {
-		return c.Reply(ErrNoEnvironments)
+		return c.Reply(h.t(c, ErrNoEnvironments))
 	}
 
 	message := strings.Join(standInfos, "\n")
 
-	return c.Reply(message)
+	return c.Reply(message, telebot.ModeHTML)
 }
 
 func (h *Handler) Claim(c telebot.Context) error {
@@ -197,11 +421,20 @@ func (h *Handler) Claim(c telebot.Context) error {
 	}
 
 	if c.Callback() != nil {
-		standName := c.Message().Payload
+		standName := callbackFrom(c).arg(0)
 		senderUsername := c.Callback().Sender.Username
 
 		if err := h.repo.CreateUser(senderUsername); err != nil {
-			return c.Edit(fmt.Sprintf("failed to create user: %v", err))
+			return h.answer(c, h.tpl(c, ErrFailedToAddUser, tplData{Err: err.Error()}))
+		}
+
+		allowed, err := h.canClaim(standName, senderUsername)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return h.answer(c, h.tpl(c, ErrStandNotAllowed, tplData{User: senderUsername}))
 		}
 
 		for _, stand := range stands {
@@ -213,15 +446,20 @@ func (h *Handler) Claim(c telebot.Context) error {
 					}
 
 					if err := h.repo.ClaimStand(standToClaim); err != nil {
-						return c.Edit(fmt.Sprintf(ErrFailedToClaim, err))
+						return h.answer(c, h.tpl(c, ErrFailedToClaim, tplData{Err: err.Error()}))
 					}
 
-					return c.Edit(fmt.Sprintf(TplStandClaimed, senderUsername, standName))
+					h.refreshDashboards()
+
+					return h.answer(c, h.tpl(c, TplStandClaimed, tplData{
+						User:  senderUsername,
+						Stand: tplStand{Name: standName},
+					}))
 				}
-				return c.Edit(ErrStandBusy)
+				return h.answer(c, h.t(c, ErrStandBusy))
 			}
 		}
-		return c.Edit(ErrStandNotFound)
+		return h.answer(c, h.t(c, ErrStandNotFound))
 	}
 
 	buttons := make([]inlineButton, 0, len(stands))
@@ -232,17 +470,17 @@ func (h *Handler) Claim(c telebot.Context) error {
 		}
 
 		buttons = append(buttons, inlineButton{
-			text: fmt.Sprintf(TplButtonStand, EmojiComputer, stand.Name),
-			data: fmt.Sprintf("claim:%s", stand.Name),
+			text: h.tpl(c, TplButtonStand, tplData{Stand: newTplStand(stand)}),
+			data: h.callbacks.encode(c.Sender().ID, "claim", stand.Name),
 		})
 	}
 
 	if len(buttons) == 0 {
-		return c.Reply(ErrNoFreeStands)
+		return c.Reply(h.t(c, ErrNoFreeStands))
 	}
 
 	menu := createInlineKeyboard(buttons)
-	return c.Reply(MsgChooseStand, &telebot.ReplyMarkup{
+	return h.sendMenu(c, h.t(c, MsgChooseStand), &telebot.ReplyMarkup{
 		InlineKeyboard: menu,
 	})
 }
@@ -254,19 +492,42 @@ func (h *Handler) Release(c telebot.Context) error {
 	}
 
 	if c.Callback() != nil {
-		standName := c.Message().Payload
+		standName := callbackFrom(c).arg(0)
 		senderUsername := c.Callback().Sender.Username
 
+		owned := false
+		for _, stand := range stands {
+			if stand.Name == standName && !stand.Released && stand.OwnerUsername.String == senderUsername {
+				owned = true
+				break
+			}
+		}
+
+		if !owned {
+			return h.answer(c, h.t(c, ErrNotStandOwner))
+		}
+
+		if h.needsConfirm(c, "release") {
+			origin := callbackFrom(c).arg(1)
+			question := h.tpl(c, TplConfirmRelease, tplData{Stand: tplStand{Name: standName}})
+			return h.askConfirm(c, origin != originDashboard, question, "release", standName)
+		}
+
 		standToRelease := entity.Stand{
 			Name:          standName,
 			OwnerUsername: sql.NullString{String: senderUsername},
 		}
 
 		if err := h.repo.ReleaseStand(standToRelease); err != nil {
-			return c.Edit(fmt.Sprintf(ErrFailedToRelease, err))
+			return h.answer(c, h.tpl(c, ErrFailedToRelease, tplData{Err: err.Error()}))
 		}
 
-		return c.Edit(fmt.Sprintf(TplStandReleased, senderUsername, standName))
+		h.refreshDashboards()
+
+		return h.answer(c, h.tpl(c, TplStandReleased, tplData{
+			User:  senderUsername,
+			Stand: tplStand{Name: standName},
+		}))
 	}
 
 	buttons := make([]inlineButton, 0, len(stands))
@@ -278,17 +539,17 @@ func (h *Handler) Release(c telebot.Context) error {
 		}
 
 		buttons = append(buttons, inlineButton{
-			text: fmt.Sprintf(TplButtonStand, EmojiComputer, stand.Name),
-			data: fmt.Sprintf("release:%s", stand.Name),
+			text: h.tpl(c, TplButtonStand, tplData{Stand: newTplStand(stand)}),
+			data: h.callbacks.encode(c.Sender().ID, "release", stand.Name),
 		})
 	}
 
 	if len(buttons) == 0 {
-		return c.Reply(ErrNoStandsToRelease)
+		return c.Reply(h.t(c, ErrNoStandsToRelease))
 	}
 
 	menu := createInlineKeyboard(buttons)
-	return c.Reply(MsgChooseToRelease, &telebot.ReplyMarkup{
+	return h.sendMenu(c, h.t(c, MsgChooseToRelease), &telebot.ReplyMarkup{
 		InlineKeyboard: menu,
 	})
 }
@@ -311,17 +572,20 @@ func (h *Handler) FeaturesState(c telebot.Context) error {
 
 	states, err := h.gitlabWrapper.GetFeaturesWithStateAsync(ctx, environments)
 	if err != nil {
-		return c.Reply(fmt.Sprintf("failed to get features state: %v", err))
+		return c.Reply(h.tpl(c, ErrFeaturesState, tplData{Err: err.Error()}))
 	}
 
 	if len(states) == 0 {
-		return c.Reply("no feature branches found")
+		return c.Reply(h.t(c, ErrNoFeatures))
 	}
 
 	features := make([]string, len(states))
 
 	for branch, state := range states {
-		features = append(features, fmt.Sprintf(TplFeatureState, branch, state))
+		features = append(features, h.tpl(c, TplFeatureState, tplData{
+			Branch: branch,
+			State:  string(state),
+		}))
 	}
 
 	message := strings.Join(features, "\n")
@@ -329,10 +593,9 @@ func (h *Handler) FeaturesState(c telebot.Context) error {
 }
 
 func (h *Handler) Greetings(c telebot.Context) error {
-	return c.Send(fmt.Sprintf(
-		TplGreetings,
-		c.Message().UserJoined.Username,
-	))
+	return c.Send(h.tpl(c, TplGreetings, tplData{
+		User: c.Message().UserJoined.Username,
+	}))
 }
 
 // workaround for onuserleft event, without it we got npe
@@ -340,23 +603,6 @@ func (h *Handler) Stub(c telebot.Context) error {
 	return nil
 }
 
-func (h *Handler) CommandHandlers() map[string]telebot.HandlerFunc {
-	return map[string]telebot.HandlerFunc{
-		"/list":           h.ListStands,
-		"/ping":           h.Ping,
-		"/ping_all":       h.PingAll,
-		"/features_state": h.FeaturesState,
-	}
-}
-
-func (h *Handler) CallbackHandlers() map[string]telebot.HandlerFunc {
-	return map[string]telebot.HandlerFunc{
-		"/claim":   h.Claim,
-		"/release": h.Release,
-		"/ping":    h.Ping,
-	}
-}
-
 func (h *Handler) Bot() *Bot {
 	return h.bot
 }
@@ -372,12 +618,27 @@ func (h *Handler) checkStands(c telebot.Context) ([]entity.Stand, error) {
 	}
 
 	if len(stands) == 0 {
-		return nil, c.Reply(ErrNoEnvironments)
+		// callbacks of inline messages come without a message to reply to
+		if c.Callback() != nil && c.Message() == nil {
+			return nil, h.toast(c, h.t(c, ErrNoEnvironments))
+		}
+
+		return nil, c.Reply(h.t(c, ErrNoEnvironments))
 	}
 
 	return stands, nil
 }
 
+// answer reports outcome of a callback by editing the menu message, or
+// with a toast for dashboard buttons so the dashboard itself stays intact
+func (h *Handler) answer(c telebot.Context, text string) error {
+	if callbackFrom(c).arg(1) != originDashboard {
+		return c.Edit(text)
+	}
+
+	return h.toast(c, text)
+}
+
 func createInlineKeyboard(items []inlineButton) [][]telebot.InlineButton {
 	var (
 		menu = make([][]telebot.InlineButton, 0, (len(items)+1)/2)
@@ -404,26 +665,35 @@ func createInlineKeyboard(items []inlineButton) [][]telebot.InlineButton {
 	return menu
 }
 
-func formatStandStatus(stand entity.Stand) string {
+// renderStandList renders a line of HTML for every stand, names are
+// padded to the widest one so statuses line up
+func (h *Handler) renderStandList(lang i18n.Lang, stands []entity.Stand) []string {
+	width := 0
+	for _, stand := range stands {
+		width = max(width, utf8.RuneCountInString(stand.Name))
+	}
+
+	lines := make([]string, 0, len(stands))
+
+	for _, stand := range stands {
+		if stand.Name == "" {
+			continue
+		}
+
+		lines = append(lines, h.text(lang, TplStandInfo, tplData{
+			Stand:  newTplStand(stand),
+			Status: h.formatStandStatus(lang, stand),
+			Width:  width,
+		}))
+	}
+
+	return lines
+}
+
+func (h *Handler) formatStandStatus(lang i18n.Lang, stand entity.Stand) string {
 	if !stand.Released {
-		if !stand.TimeClaimed.Valid {
-			return fmt.Sprintf(
-				TplStandBusyBy,
-				stand.OwnerUsername.String,
-				0,
-				EmojiBusy,
-			)
-		}
-
-		timeBusy := time.Since(stand.TimeClaimed.Time)
-
-		return fmt.Sprintf(
-			TplStandBusyBy,
-			stand.OwnerUsername.String,
-			int(timeBusy.Hours()),
-			EmojiBusy,
-		)
+		return h.text(lang, TplStandBusyBy, tplData{Stand: newTplStand(stand)})
 	}
 
-	return fmt.Sprintf(TplStandFree, EmojiFree)
+	return h.text(lang, TplStandFree, nil)
 }
@@ -0,0 +1,64 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/telebot.v4"
+)
+
+const inlineCacheTime = 10
+
+// InlineQuery answers `@bot <stand>` from any chat with statuses of
+// matching stands, free ones get a claim button anybody may press
+func (h *Handler) InlineQuery(c telebot.Context) error {
+	stands, err := h.repo.Stands()
+	if err != nil {
+		return err
+	}
+
+	query := strings.ToLower(strings.TrimSpace(c.Query().Text))
+	lang := h.lang(c)
+
+	results := make(telebot.Results, 0, len(stands))
+
+	for _, stand := range stands {
+		if stand.Name == "" || !strings.Contains(strings.ToLower(stand.Name), query) {
+			continue
+		}
+
+		status := h.formatStandStatus(lang, stand)
+
+		result := &telebot.ArticleResult{
+			Title:       stand.Name,
+			Description: status,
+			Text: h.text(lang, TplStandInfo, tplData{
+				Stand:  newTplStand(stand),
+				Status: status,
+			}),
+		}
+		result.SetParseMode(telebot.ModeHTML)
+
+		if stand.Released {
+			result.SetReplyMarkup(&telebot.ReplyMarkup{
+				InlineKeyboard: [][]telebot.InlineButton{{{
+					Text: h.text(lang, TplButtonClaim, tplData{Stand: newTplStand(stand)}),
+					Data: h.callbacks.encode(anyone, "claim", stand.Name),
+				}}},
+			})
+		}
+
+		results = append(results, result)
+	}
+
+	err = c.Answer(&telebot.QueryResponse{
+		Results:    results,
+		CacheTime:  inlineCacheTime,
+		IsPersonal: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to answer inline query: %w", err)
+	}
+
+	return nil
+}
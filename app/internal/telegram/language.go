@@ -0,0 +1,252 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	langCtxKey = "lang"
+
+	languageScopeChat = "chat"
+	languageScopeUser = "user"
+
+	// the cache is reset on change by this process only, other replicas
+	// behind the same webhook see a new language once it expires
+	languageTTL = time.Minute
+)
+
+type languageKey struct {
+	chatID   int64
+	username string
+}
+
+type cachedLanguage struct {
+	lang    string
+	expires time.Time
+}
+
+// languages caches languages resolved for chat and user, so an update
+// doesn't cost a query. The cache is per process
+type languages struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	langs map[languageKey]cachedLanguage
+}
+
+func newLanguages(ttl time.Duration) *languages {
+	return &languages{
+		ttl:   ttl,
+		langs: make(map[languageKey]cachedLanguage),
+	}
+}
+
+func (l *languages) get(key languageKey, now time.Time) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cached, ok := l.langs[key]
+	if !ok || now.After(cached.expires) {
+		delete(l.langs, key)
+		return "", false
+	}
+
+	return cached.lang, true
+}
+
+func (l *languages) set(key languageKey, lang string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for k, cached := range l.langs {
+		if now.After(cached.expires) {
+			delete(l.langs, k)
+		}
+	}
+
+	l.langs[key] = cachedLanguage{lang: lang, expires: now.Add(l.ttl)}
+}
+
+// reset drops everything cached, languages change seldom
+func (l *languages) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	clear(l.langs)
+}
+
+// LanguageMiddleware resolves language of the update, user's choice
+// wins over chat's one, and stores it in the context for handlers
+func LanguageMiddleware(h *Handler) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			var (
+				chatID   int64
+				username string
+			)
+
+			if c.Chat() != nil {
+				chatID = c.Chat().ID
+			}
+			if c.Sender() != nil {
+				username = c.Sender().Username
+			}
+
+			if parsed, ok := i18n.Parse(h.language(chatID, username)); ok {
+				c.Set(langCtxKey, parsed)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Language lets user choose language of the chat, or only their own one
+// with `/language me`. Language of a group is chosen by admins only
+func (h *Handler) Language(c telebot.Context) error {
+	if c.Callback() != nil {
+		data := callbackFrom(c)
+
+		if data.arg(1) != languageScopeUser {
+			allowed, err := h.canSetChatLanguage(c)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return h.toast(c, h.tpl(c, ErrRoleRequired, tplData{Text: entity.RoleAdmin}))
+			}
+		}
+
+		lang, ok := i18n.Parse(data.arg(0))
+		if !ok {
+			return h.answer(c, h.tpl(c, ErrUnknownLanguage, tplData{Text: data.arg(0)}))
+		}
+
+		if data.arg(1) == languageScopeUser {
+			username := c.Sender().Username
+			if err := h.repo.SetUserLanguage(username, string(lang)); err != nil {
+				return err
+			}
+			h.languages.reset()
+			return c.Edit(h.text(lang, TplUserLanguageSet, tplData{
+				User:     username,
+				Language: h.text(lang, MsgLanguageName, nil),
+			}))
+		}
+
+		if err := h.repo.SetChatLanguage(c.Chat().ID, string(lang)); err != nil {
+			return err
+		}
+		h.languages.reset()
+
+		h.refreshDashboard(c.Chat().ID)
+
+		return c.Edit(h.text(lang, TplChatLanguageSet, tplData{
+			Language: h.text(lang, MsgLanguageName, nil),
+		}))
+	}
+
+	scope := languageScopeChat
+	if c.Message().Payload == "me" {
+		scope = languageScopeUser
+	}
+
+	if scope == languageScopeChat {
+		allowed, err := h.canSetChatLanguage(c)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return c.Reply(h.tpl(c, ErrRoleRequired, tplData{Text: entity.RoleAdmin}))
+		}
+	}
+
+	buttons := make([]inlineButton, 0, len(i18n.Supported()))
+
+	for _, lang := range i18n.Supported() {
+		buttons = append(buttons, inlineButton{
+			text: h.text(lang, MsgLanguageName, nil),
+			data: h.callbacks.encode(c.Sender().ID, "language", string(lang), scope),
+		})
+	}
+
+	menu := createInlineKeyboard(buttons)
+	return h.sendMenu(c, h.t(c, MsgChooseLanguage), &telebot.ReplyMarkup{
+		InlineKeyboard: menu,
+	})
+}
+
+// canSetChatLanguage reports whether sender may change language of the
+// chat, in private chats it's their own one
+func (h *Handler) canSetChatLanguage(c telebot.Context) (bool, error) {
+	if c.Chat().Type == telebot.ChatPrivate {
+		return true, nil
+	}
+
+	role, err := h.role(c)
+	if err != nil {
+		return false, err
+	}
+
+	return entity.RoleAtLeast(role, entity.RoleAdmin), nil
+}
+
+// language resolves language of user in the chat, user's choice wins over
+// chat's one. Empty username gives language of the chat
+func (h *Handler) language(chatID int64, username string) string {
+	var (
+		key = languageKey{chatID: chatID, username: username}
+		now = time.Now()
+	)
+
+	if lang, ok := h.languages.get(key, now); ok {
+		return lang
+	}
+
+	lang, err := h.repo.Language(chatID, username)
+	if err != nil {
+		log.Zap().Errorf("failed to resolve language: %v", err)
+		return lang
+	}
+
+	h.languages.set(key, lang, now)
+
+	return lang
+}
+
+// lang returns language resolved by LanguageMiddleware
+func (h *Handler) lang(c telebot.Context) i18n.Lang {
+	if lang, ok := c.Get(langCtxKey).(i18n.Lang); ok {
+		return lang
+	}
+	return i18n.Default
+}
+
+// t renders message without parameters in language of the update
+func (h *Handler) t(c telebot.Context, key i18n.Key) string {
+	return h.text(h.lang(c), key, nil)
+}
+
+// tpl renders message template in language of the update
+func (h *Handler) tpl(c telebot.Context, key i18n.Key, data tplData) string {
+	return h.text(h.lang(c), key, data)
+}
+
+func (h *Handler) text(lang i18n.Lang, key i18n.Key, data any) string {
+	return h.messages.Text(lang, key, data)
+}
+
+// chatLanguage is used where there is no update to take language from,
+// e.g. in workers
+func (h *Handler) chatLanguage(chatID int64) i18n.Lang {
+	if parsed, ok := i18n.Parse(h.language(chatID, "")); ok {
+		return parsed
+	}
+
+	return i18n.Default
+}
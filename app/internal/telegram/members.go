@@ -0,0 +1,184 @@
+package telegram
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+// ChatMember reconciles users with chat_member updates, unlike service
+// messages they come for invite links, kicks and promotions too. The bot
+// has to be an administrator of the chat to get them
+func (h *Handler) ChatMember(c telebot.Context) error {
+	update := c.ChatMember()
+	if update == nil || update.NewChatMember == nil || update.Chat == nil {
+		return nil
+	}
+
+	user := update.NewChatMember.User
+	if user != nil {
+		h.memberRoles.forget(memberKey{chatID: update.Chat.ID, userID: user.ID})
+	}
+
+	if isMember(update.NewChatMember) {
+		if !isMember(update.OldChatMember) {
+			log.Zap().Infof("%s joined chat %d", displayName(user), update.Chat.ID)
+		} else if update.NewChatMember.Role != update.OldChatMember.Role {
+			log.Zap().Infof("%s is %s in chat %d now", displayName(user), update.NewChatMember.Role, update.Chat.ID)
+		}
+
+		h.memberJoined(update.Chat.ID, user)
+		return nil
+	}
+
+	if isMember(update.OldChatMember) {
+		log.Zap().Infof("%s left chat %d: %s", displayName(user), update.Chat.ID, update.NewChatMember.Role)
+		h.memberLeft(user)
+	}
+
+	return nil
+}
+
+// SyncMembers backfills users of the chat: administrators are added and
+// users the bot has seen here are checked to still be members
+func (h *Handler) SyncMembers(c telebot.Context) error {
+	chat := c.Chat()
+	if chat.Type == telebot.ChatPrivate {
+		return c.Reply(h.t(c, ErrGroupOnly))
+	}
+
+	admins, err := h.bot.Tele().AdminsOf(chat)
+	if err != nil {
+		return c.Reply(h.tpl(c, ErrFailedToSyncMembers, tplData{Err: err.Error()}))
+	}
+
+	var (
+		synced  []string
+		removed []string
+		checked = make(map[int64]bool, len(admins))
+	)
+
+	for _, admin := range admins {
+		if h.memberJoined(chat.ID, admin.User) {
+			synced = append(synced, admin.User.Username)
+		}
+		if admin.User != nil {
+			checked[admin.User.ID] = true
+		}
+	}
+
+	known, err := h.repo.ChatUsers(chat.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range known {
+		if checked[user.UserID.Int64] {
+			continue
+		}
+
+		member, err := h.bot.Tele().ChatMemberOf(chat, &telebot.User{ID: user.UserID.Int64})
+		if err != nil {
+			log.Zap().Warnf("failed to get chat member %s: %v", user.Username, err)
+			continue
+		}
+
+		if isMember(member) {
+			synced = append(synced, user.Username)
+			continue
+		}
+
+		// username may be gone or changed since, the stored one is deleted
+		h.memberLeft(&telebot.User{ID: user.UserID.Int64, Username: user.Username})
+		removed = append(removed, user.Username)
+	}
+
+	return c.Reply(h.tpl(c, TplMembersSynced, tplData{
+		Users:   synced,
+		Removed: removed,
+	}))
+}
+
+// memberJoined creates user or updates their ids, false means the user
+// can't be stored as they have no username
+func (h *Handler) memberJoined(chatID int64, user *telebot.User) bool {
+	if user == nil || user.IsBot || user.Username == "" {
+		return false
+	}
+
+	err := h.repo.TouchUser(entity.User{
+		Username:   user.Username,
+		UserID:     sql.NullInt64{Int64: user.ID, Valid: true},
+		TeamChatID: sql.NullInt64{Int64: chatID, Valid: true},
+	})
+	if err != nil {
+		log.Zap().Errorf("failed to add member %s: %v", user.Username, err)
+		return false
+	}
+
+	return true
+}
+
+// memberLeft deletes user, stands they held are released
+func (h *Handler) memberLeft(user *telebot.User) {
+	if user == nil || user.IsBot || user.Username == "" {
+		return
+	}
+
+	released, err := h.repo.DeleteUser(user.Username)
+	if err != nil {
+		log.Zap().Errorf("failed to delete member %s: %v", user.Username, err)
+		return
+	}
+
+	if len(released) == 0 {
+		return
+	}
+
+	log.Zap().Infof("released stands of %s who left: %s", user.Username, strings.Join(released, ", "))
+
+	for _, standName := range released {
+		err := h.repo.AddStandEvent(entity.StandEvent{
+			StandName:     standName,
+			Kind:          entity.EventMemberLeft,
+			OwnerUsername: sql.NullString{String: user.Username, Valid: true},
+		})
+		if err != nil {
+			log.Zap().Errorf("failed to record release of %s: %v", standName, err)
+		}
+	}
+
+	h.refreshDashboards()
+}
+
+// isMember tells whether user is in the chat, restricted users may be
+// members too
+func isMember(member *telebot.ChatMember) bool {
+	if member == nil {
+		return false
+	}
+
+	switch member.Role {
+	case telebot.Creator, telebot.Administrator, telebot.Member:
+		return true
+	case telebot.Restricted:
+		return member.Member
+	default:
+		return false
+	}
+}
+
+func displayName(user *telebot.User) string {
+	if user == nil {
+		return ""
+	}
+
+	if user.Username == "" {
+		return user.FirstName
+	}
+
+	return "@" + user.Username
+}
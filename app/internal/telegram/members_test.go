@@ -0,0 +1,52 @@
+package telegram
+
+import (
+	"testing"
+
+	"gopkg.in/telebot.v4"
+)
+
+func TestIsMember(t *testing.T) {
+	tests := []struct {
+		name   string
+		member *telebot.ChatMember
+		want   bool
+	}{
+		{name: "nil", member: nil, want: false},
+		{name: "creator", member: &telebot.ChatMember{Role: telebot.Creator}, want: true},
+		{name: "administrator", member: &telebot.ChatMember{Role: telebot.Administrator}, want: true},
+		{name: "member", member: &telebot.ChatMember{Role: telebot.Member}, want: true},
+		{name: "restricted member", member: &telebot.ChatMember{Role: telebot.Restricted, Member: true}, want: true},
+		{name: "restricted who left", member: &telebot.ChatMember{Role: telebot.Restricted}, want: false},
+		{name: "left", member: &telebot.ChatMember{Role: telebot.Left}, want: false},
+		{name: "kicked", member: &telebot.ChatMember{Role: telebot.Kicked}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isMember(tt.member); got != tt.want {
+				t.Errorf("isMember() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		name string
+		user *telebot.User
+		want string
+	}{
+		{name: "nil", user: nil, want: ""},
+		{name: "username", user: &telebot.User{Username: "alice", FirstName: "Alice"}, want: "@alice"},
+		{name: "no username", user: &telebot.User{FirstName: "Bob"}, want: "Bob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayName(tt.user); got != tt.want {
+				t.Errorf("displayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
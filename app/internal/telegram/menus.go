@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+const defaultMenuTTL = 10 * time.Minute
+
+// sendMenu replies to the command with a keyboard and remembers it, so the
+// menu and the command go away once a choice is made or the menu expires
+func (h *Handler) sendMenu(c telebot.Context, text string, markup *telebot.ReplyMarkup) error {
+	command := c.Message()
+
+	msg, err := h.bot.Tele().Reply(command, text, markup, messageTopic(command))
+	if err != nil {
+		return err
+	}
+
+	menu := entity.Menu{
+		ChatID:    msg.Chat.ID,
+		MessageID: msg.ID,
+		Expires:   time.Now().Add(h.menuTTL),
+	}
+
+	// menus sent for buttons, e.g. confirmations, have no command to delete
+	if c.Callback() == nil {
+		menu.CommandMessageID = sql.NullInt64{Int64: int64(command.ID), Valid: true}
+	}
+
+	if err := h.repo.SaveMenu(menu); err != nil {
+		log.Zap().Errorf("failed to save menu: %v", err)
+	}
+
+	return nil
+}
+
+// closeMenu is called after a menu button was handled, the menu already
+// shows the result so only the command message is deleted
+func (h *Handler) closeMenu(c telebot.Context) {
+	msg := c.Message()
+	if msg == nil || msg.Chat == nil {
+		return
+	}
+
+	menu, found, err := h.repo.Menu(msg.Chat.ID, msg.ID)
+	if err != nil {
+		log.Zap().Errorf("failed to get menu: %v", err)
+		return
+	}
+
+	if !found {
+		return
+	}
+
+	h.deleteCommand(menu)
+
+	if err := h.repo.DeleteMenu(menu.ChatID, menu.MessageID); err != nil {
+		log.Zap().Errorf("failed to delete menu: %v", err)
+	}
+}
+
+// CleanupMenus deletes expired menus with their commands, a menu the bot
+// can't delete anymore is collapsed to plain text
+func (h *Handler) CleanupMenus() error {
+	menus, err := h.repo.ExpiredMenus(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, menu := range menus {
+		msg := storedMessage(menu.ChatID, menu.MessageID)
+
+		if err := h.bot.Tele().Delete(msg); err != nil {
+			_, err := h.bot.Tele().EditReplyMarkup(msg, nil)
+			if err != nil {
+				log.Zap().Warnf("failed to remove menu %d in chat %d: %v", menu.MessageID, menu.ChatID, err)
+			}
+		}
+
+		h.deleteCommand(menu)
+
+		if err := h.repo.DeleteMenu(menu.ChatID, menu.MessageID); err != nil {
+			return fmt.Errorf("failed to forget menu: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteCommand removes command message the menu was sent for, the bot
+// needs the right to delete messages in groups, so failures are expected
+func (h *Handler) deleteCommand(menu entity.Menu) {
+	if !menu.CommandMessageID.Valid {
+		return
+	}
+
+	msg := storedMessage(menu.ChatID, int(menu.CommandMessageID.Int64))
+
+	if err := h.bot.Tele().Delete(msg); err != nil {
+		log.Zap().Debugf("failed to delete command message in chat %d: %v", menu.ChatID, err)
+	}
+}
+
+func storedMessage(chatID int64, messageID int) telebot.StoredMessage {
+	return telebot.StoredMessage{
+		MessageID: strconv.Itoa(messageID),
+		ChatID:    chatID,
+	}
+}
@@ -0,0 +1,353 @@
+package telegram
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/config"
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/pkg/entity"
+)
+
+// tplData is what every message template is rendered with
+type tplData struct {
+	User     string
+	Users    []string
+	Stand    tplStand
+	Stands   []tplStand
+	Status   string
+	Hours    int
+	Language string
+	Branch   string
+	State    string
+	Err      string
+	Text     string
+	Time     time.Time
+	// width stand names are padded to in lists
+	Width int
+	// users removed from the chat, e.g. by /sync_members
+	Removed []string
+}
+
+type tplStand struct {
+	Name     string
+	Owner    string
+	Released bool
+	Claimed  time.Time
+	Hours    int
+	Held     time.Duration
+	// reminders about the stand are paused till this time, zero if not
+	AckUntil time.Time
+	// the stand is released automatically at this time, zero if not
+	ReleaseAt time.Time
+}
+
+func newTplStand(stand entity.Stand) tplStand {
+	s := tplStand{
+		Name:     stand.Name,
+		Owner:    stand.OwnerUsername.String,
+		Released: stand.Released,
+	}
+
+	if stand.TimeClaimed.Valid {
+		s.Claimed = stand.TimeClaimed.Time
+		s.Held = time.Since(stand.TimeClaimed.Time)
+		s.Hours = int(s.Held.Hours())
+	}
+
+	if stand.ReminderAckUntil.Valid && time.Now().Before(stand.ReminderAckUntil.Time) {
+		s.AckUntil = stand.ReminderAckUntil.Time
+	}
+
+	if stand.ReleaseAt.Valid && !stand.Released {
+		s.ReleaseAt = stand.ReleaseAt.Time
+	}
+
+	return s
+}
+
+var tplFuncs = template.FuncMap{
+	"mention": func(username string) string {
+		return "@" + username
+	},
+	"mentions": func(usernames []string) string {
+		mentions := make([]string, 0, len(usernames))
+		for _, username := range usernames {
+			mentions = append(mentions, "@"+username)
+		}
+		return strings.Join(mentions, ", ")
+	},
+}
+
+// NewMessages compiles default messages with overrides from config
+func NewMessages(cfg *config.Config) (*i18n.Templates, error) {
+	overrides := make(i18n.Catalogue, len(cfg.Bot.Templates))
+
+	for code, msgs := range cfg.Bot.Templates {
+		lang := i18n.Lang(code)
+
+		overrides[lang] = make(map[i18n.Key]string, len(msgs))
+		for key, src := range msgs {
+			overrides[lang][i18n.Key(key)] = src
+		}
+	}
+
+	return i18n.Compile(defaultMessages, overrides, tplFuncs, tplData{})
+}
+
+var defaultMessages = i18n.Catalogue{
+	i18n.English: {
+		ErrNoEnvironments:      "no environments found",
+		ErrNoBusyStands:        "no busy stands found",
+		ErrNoFreeStands:        "no free stands available",
+		ErrStandBusy:           "stand is busy, choose another free one",
+		ErrStandNotFound:       "stand not found",
+		ErrNoStandsToRelease:   "you have no stands to release",
+		ErrNoOwnStands:         "you don't hold any stands",
+		ErrFailedToClaim:       "failed to claim stand: {{.Err}}",
+		ErrFailedToRelease:     "failed to release stand: {{.Err}}",
+		ErrFailedToAddUser:     "failed to create user: {{.Err}}",
+		ErrFeaturesState:       "failed to get features state: {{.Err}}",
+		ErrNoFeatures:          "no feature branches found",
+		ErrButtonExpired:       "this button has expired, run the command again",
+		ErrMenuNotYours:        "this menu isn't yours",
+		ErrNotStandOwner:       "you don't own this stand",
+		ErrUnknownLanguage:     "unknown language {{printf \"%q\" .Text}}",
+		ErrNoUsername:          "set a telegram username first, stands are tracked by it",
+		ErrStartBotFirst:       "start me in private chat first: t.me/{{.Text}}",
+		ErrTransferUsage:       "usage: /transfer <stand> @username",
+		ErrTransferStale:       "{{mention .Stand.Owner}} doesn't hold {{.Stand.Name}} anymore",
+		ErrFailedToTransfer:    "failed to transfer stand: {{.Err}}",
+		ErrRoleRequired:        "this needs {{.Text}} role",
+		ErrStandNotAllowed:     "@{{.User}} isn't in the allowlist of this stand",
+		ErrRolesUsage:          "usage: /roles, /roles set @user admin|member|viewer, /roles default member|viewer, /roles allow|disallow <stand> @user",
+		ErrGroupOnly:           "this works in group chats only",
+		ErrWorkingHoursUsage:   "{{.Err}}, use /working_hours 09:00-18:00 [mon,tue,wed,thu,fri] [Europe/Moscow], /working_hours off or /working_hours reset",
+		ErrFailedToSyncMembers: "failed to sync members: {{.Err}}",
+		ErrNotInTopic:          "send it in the topic reminders and the dashboard should go to, or use /stands_topic off",
+		ErrForceReleaseUsage:   "usage: /force_release <stand> <reason>",
+		ErrAdminsOnly:          "only chat administrators may do this",
+		ErrStandNotBusy:        "stand isn't busy",
+
+		MsgChooseStand:         "choose stand to claim:",
+		MsgChooseToRelease:     "choose stand to release:",
+		MsgMyStandsTitle:       "your stands:",
+		MsgChooseUserToPing:    "choose user to ping:",
+		MsgChooseLanguage:      "choose language:",
+		MsgLanguageName:        "English",
+		MsgStartPrivate:        "Hi! All commands work here too, against your team's stands. Where should I send your reminders?",
+		MsgChooseNotifications: "where should I send your reminders?",
+		MsgButtonNotifyPrivate: "In private messages",
+		MsgButtonNotifyGroup:   "In the group chat",
+		MsgNotifyPrivateSet:    "reminders will come to private messages",
+		MsgNotifyGroupSet:      "reminders will come to the group chat",
+		MsgDigestBusy:          "Busy:",
+		MsgHelpTitle:           "<b>Commands</b>",
+		MsgStandsTopicSet:      "reminders and the dashboard will go to this topic",
+		MsgStandsTopicReset:    "reminders and the dashboard will go to the topic of the last command",
+		MsgSettingsSaved:       "saved",
+		MsgQuietHoursOff:       "No quiet hours",
+		MsgWorkingHoursAnyTime: "reminders and the digest are sent any time",
+		MsgButtonYes:           "Yes",
+		MsgButtonNo:            "No",
+		MsgCancelled:           "cancelled",
+		MsgButtonAccept:        "Accept",
+		MsgButtonDecline:       "Decline",
+
+		TplStandClaimed:        "{{mention .User}} has claimed {{.Stand.Name}}",
+		TplStandReleased:       "{{mention .User}} has released {{.Stand.Name}}",
+		TplPingUser:            "{{mention .User}} would you mind releasing your stands??",
+		TplPingAllUsers:        "{{range $i, $s := .Stands}}{{if $i}}, {{end}}{{mention $s.Owner}}: {{$s.Name}}{{end}}, would you mind releasing your stands?",
+		TplNotify:              "{{mentions .Users}}, would you mind to release the stand? It's been busy for more than {{.Hours}} {{plural .Hours \"hour\" \"hours\"}}",
+		TplNotifyAdmins:        "{{mentions .Users}}, busy for more than {{.Hours}} {{plural .Hours \"hour\" \"hours\"}}: {{range $i, $s := .Stands}}{{if $i}}, {{end}}{{$s.Name}} (@{{$s.Owner}}){{end}}. Consider /force_release",
+		TplReleaseWarning:      "{{mentions .Users}}, busy for more than {{.Hours}} {{plural .Hours \"hour\" \"hours\"}}: {{range $i, $s := .Stands}}{{if $i}}, {{end}}{{$s.Name}}{{end}}. Releasing at {{.Time.Format \"15:04\"}} unless you tap Keep",
+		TplReleaseKept:         "{{mention .User}} keeps {{.Stand.Name}}, next release warning not before {{.Time.Format \"15:04\"}}",
+		TplAutoReleased:        "{{.Stand.Name}} was released automatically as {{mention .Stand.Owner}} didn't keep it",
+		TplAutoReleasedOwner:   "your stand {{.Stand.Name}} was released automatically as you didn't keep it",
+		TplStandBusyBy:         "busy by {{mention .Stand.Owner}} for {{duration .Stand.Held}}, since {{since .Stand.Claimed \"yesterday\" \"Jan 2 15:04\"}} " + EmojiBusy,
+		TplStandFree:           "is free " + EmojiFree,
+		TplGreetings:           "Hello {{mention .User}}, I'm StandClaimer bot, I will help you to manage environments across the team. Tap `/` on the group menu to see commands",
+		TplStandInfo:           EmojiComputer + " <code>{{pad .Stand.Name .Width | html}}</code> {{.Status}}",
+		TplButtonStand:         EmojiComputer + " {{.Stand.Name}}",
+		TplButtonUser:          "{{mention .Stand.Owner}} ({{.Stand.Name}})",
+		TplFeatureState:        "feature: {{.Branch}} {{.State}}",
+		TplDashboardTitle:      "Stands (updated at {{.Time.Format \"15:04\"}})",
+		TplButtonClaim:         "Claim {{.Stand.Name}}",
+		TplButtonRelease:       "Release {{.Stand.Name}}",
+		TplChatLanguageSet:     "chat language is set to {{.Language}}",
+		TplUserLanguageSet:     "{{mention .User}}, your language is set to {{.Language}}",
+		TplReminderRelease:     "Release {{.Stand.Name}} now",
+		TplReminderExtend:      "Still need {{.Stand.Name}} +4h",
+		TplReminderKeep:        "Keep {{.Stand.Name}}",
+		TplReminderSnooze:      "Snooze until tomorrow",
+		TplReminderExtended:    "{{mention .User}} still needs {{.Stand.Name}}, next reminder not before {{.Time.Format \"15:04\"}}",
+		TplReminderSnoozed:     "{{mention .User}} snoozed reminders about {{.Stand.Name}} until {{.Time.Format \"Jan 2 15:04\"}}",
+		TplTransferOffer:       "{{mention .User}}, {{mention .Stand.Owner}} wants to hand {{.Stand.Name}} over to you",
+		TplTransferAccepted:    "{{mention .User}} has taken {{.Stand.Name}} over from {{mention .Stand.Owner}}",
+		TplTransferDeclined:    "{{mention .User}} has declined {{.Stand.Name}} from {{mention .Stand.Owner}}",
+		TplForceReleased:       "{{mention .User}} has force released {{.Stand.Name}}{{if .Stand.Owner}} from {{mention .Stand.Owner}}{{end}}: {{.Text}}",
+		TplForceReleasedOwner:  "{{mention .User}} has released your stand {{.Stand.Name}}: {{.Text}}",
+		TplDigestTitle:         "<b>Stands on {{.Time.Format \"Monday, Jan 2\"}}</b>",
+		TplDigestFree:          "Free " + EmojiFree + ": {{range $i, $s := .Stands}}{{if $i}}, {{end}}{{html $s.Name}}{{end}}",
+		TplMembersSynced:       "Members synced: {{len .Users}}{{if .Removed}}, removed as they left the chat: {{join .Removed \", \"}}{{end}}",
+		TplSettings:            "settings of @{{.User}}\nreminders: {{.Status}}\nquiet hours: {{if .State}}{{.State}}{{else}}off{{end}}\nlanguage: {{.Language}}",
+		TplQuietHours:          "Quiet {{.Text}}",
+		TplWorkingHours:        "working hours: {{.Text}}, reminders and the digest wait for them",
+		TplConfirmRelease:      "Are you sure you want to release {{.Stand.Name}}?",
+		TplConfirmForceRelease: "Are you sure you want to force release {{.Stand.Name}}? Reason: {{.Text}}",
+		TplConfirmTransfer:     "Are you sure you want to hand {{.Stand.Name}} over to {{mention .User}}?",
+		TplMyStand:             "<code>{{pad .Stand.Name .Width | html}}</code> held for {{duration .Stand.Held}}, since {{since .Stand.Claimed \"yesterday\" \"Jan 2 15:04\"}}{{if not .Stand.AckUntil.IsZero}}, reminders paused till {{.Stand.AckUntil.Format \"Jan 2 15:04\"}}{{end}}{{if not .Stand.ReleaseAt.IsZero}}, released automatically at {{.Stand.ReleaseAt.Format \"Jan 2 15:04\"}}{{end}}",
+		TplRoleSet:             "{{mention .User}}: {{.Text}}",
+		TplDefaultRoleSet:      "default role: {{.Text}}",
+		TplStandAllowed:        "{{.Stand.Name}} may be claimed by {{mention .User}}",
+		TplStandDisallowed:     "{{.Stand.Name}} may not be claimed by {{mention .User}} anymore",
+		TplStartInGroup:        "write me in private to get reminders there and use commands quietly: t.me/{{.Text}}",
+		TplHelpCommand:         "{{html .Text}} - {{.Status}}",
+
+		CmdStart:         "Use the bot in private chat and get reminders there",
+		CmdHelp:          "Show available commands",
+		CmdList:          "Show all stands",
+		CmdMyStands:      "Show stands you hold with Release/Extend buttons",
+		CmdClaim:         "Claim a stand",
+		CmdRelease:       "Release your stand",
+		CmdTransfer:      "Hand your stand over to a colleague",
+		CmdPing:          "Ping owner of a stand",
+		CmdPingAll:       "Ping owners of all busy stands",
+		CmdDashboard:     "Pin live stands dashboard",
+		CmdFeaturesState: "Show current state of features",
+		CmdNotifications: "Choose where to get reminders: private messages or group",
+		CmdLanguage:      "Choose language of the chat, or yours with `me`",
+		CmdForceRelease:  "Release anyone's stand with a reason",
+		CmdRoles:         "Manage roles and stand allowlists",
+		CmdStandsTopic:   "Send reminders and the dashboard to this topic",
+		CmdSyncMembers:   "Sync members of the chat with telegram",
+		CmdWorkingHours:  "Show or set working hours reminders wait for",
+		CmdSettings:      "Your reminders, quiet hours and language",
+	},
+	i18n.Russian: {
+		ErrNoEnvironments:      "стенды не найдены",
+		ErrNoBusyStands:        "нет занятых стендов",
+		ErrNoFreeStands:        "нет свободных стендов",
+		ErrStandBusy:           "стенд занят, выберите другой свободный",
+		ErrStandNotFound:       "стенд не найден",
+		ErrNoStandsToRelease:   "у вас нет стендов, которые можно освободить",
+		ErrNoOwnStands:         "у вас нет занятых стендов",
+		ErrFailedToClaim:       "не удалось занять стенд: {{.Err}}",
+		ErrFailedToRelease:     "не удалось освободить стенд: {{.Err}}",
+		ErrFailedToAddUser:     "не удалось создать пользователя: {{.Err}}",
+		ErrFeaturesState:       "не удалось получить состояние фич: {{.Err}}",
+		ErrNoFeatures:          "фича-ветки не найдены",
+		ErrButtonExpired:       "кнопка устарела, вызовите команду ещё раз",
+		ErrMenuNotYours:        "это меню не для вас",
+		ErrNotStandOwner:       "этот стенд занят не вами",
+		ErrUnknownLanguage:     "неизвестный язык {{printf \"%q\" .Text}}",
+		ErrNoUsername:          "сначала задайте username в телеграме, стенды привязываются к нему",
+		ErrStartBotFirst:       "сначала напишите мне в личку: t.me/{{.Text}}",
+		ErrTransferUsage:       "использование: /transfer <стенд> @username",
+		ErrTransferStale:       "{{mention .Stand.Owner}} уже не занимает {{.Stand.Name}}",
+		ErrFailedToTransfer:    "не удалось передать стенд: {{.Err}}",
+		ErrRoleRequired:        "для этого нужна роль {{.Text}}",
+		ErrStandNotAllowed:     "@{{.User}} нет в списке допущенных к этому стенду",
+		ErrRolesUsage:          "использование: /roles, /roles set @user admin|member|viewer, /roles default member|viewer, /roles allow|disallow <стенд> @user",
+		ErrGroupOnly:           "это работает только в групповых чатах",
+		ErrWorkingHoursUsage:   "{{.Err}}, используйте /working_hours 09:00-18:00 [mon,tue,wed,thu,fri] [Europe/Moscow], /working_hours off или /working_hours reset",
+		ErrFailedToSyncMembers: "не удалось синхронизировать участников: {{.Err}}",
+		ErrNotInTopic:          "отправьте команду в тему, куда должны приходить напоминания и дашборд, или используйте /stands_topic off",
+		ErrForceReleaseUsage:   "использование: /force_release <стенд> <причина>",
+		ErrAdminsOnly:          "это могут делать только администраторы чата",
+		ErrStandNotBusy:        "стенд не занят",
+
+		MsgChooseStand:         "выберите стенд, который хотите занять:",
+		MsgChooseToRelease:     "выберите стенд, который хотите освободить:",
+		MsgMyStandsTitle:       "ваши стенды:",
+		MsgChooseUserToPing:    "выберите, кого пингануть:",
+		MsgChooseLanguage:      "выберите язык:",
+		MsgLanguageName:        "Русский",
+		MsgStartPrivate:        "Привет! Здесь работают все команды для стендов вашей команды. Куда присылать напоминания?",
+		MsgChooseNotifications: "куда присылать напоминания?",
+		MsgButtonNotifyPrivate: "В личные сообщения",
+		MsgButtonNotifyGroup:   "В групповой чат",
+		MsgNotifyPrivateSet:    "напоминания будут приходить в личные сообщения",
+		MsgNotifyGroupSet:      "напоминания будут приходить в групповой чат",
+		MsgDigestBusy:          "Заняты:",
+		MsgHelpTitle:           "<b>Команды</b>",
+		MsgStandsTopicSet:      "напоминания и дашборд будут приходить в эту тему",
+		MsgStandsTopicReset:    "напоминания и дашборд будут приходить в тему последней команды",
+		MsgSettingsSaved:       "сохранено",
+		MsgQuietHoursOff:       "Без тихих часов",
+		MsgWorkingHoursAnyTime: "напоминания и дайджест приходят в любое время",
+		MsgButtonYes:           "Да",
+		MsgButtonNo:            "Нет",
+		MsgCancelled:           "отменено",
+		MsgButtonAccept:        "Принять",
+		MsgButtonDecline:       "Отказаться",
+
+		TplStandClaimed:        "{{mention .User}} занял {{.Stand.Name}}",
+		TplStandReleased:       "{{mention .User}} освободил {{.Stand.Name}}",
+		TplPingUser:            "{{mention .User}}, не мог бы ты освободить свои стенды?",
+		TplPingAllUsers:        "{{range $i, $s := .Stands}}{{if $i}}, {{end}}{{mention $s.Owner}}: {{$s.Name}}{{end}}, не могли бы вы освободить свои стенды?",
+		TplNotify:              "{{mentions .Users}}, не пора ли освободить стенд? Он занят уже больше {{.Hours}} {{plural .Hours \"часа\" \"часов\" \"часов\"}}",
+		TplNotifyAdmins:        "{{mentions .Users}}, заняты больше {{.Hours}} {{plural .Hours \"часа\" \"часов\" \"часов\"}}: {{range $i, $s := .Stands}}{{if $i}}, {{end}}{{$s.Name}} (@{{$s.Owner}}){{end}}. Возможно, стоит сделать /force_release",
+		TplReleaseWarning:      "{{mentions .Users}}, заняты больше {{.Hours}} {{plural .Hours \"часа\" \"часов\" \"часов\"}}: {{range $i, $s := .Stands}}{{if $i}}, {{end}}{{$s.Name}}{{end}}. Освобожу в {{.Time.Format \"15:04\"}}, если не нажмёте Оставить",
+		TplReleaseKept:         "{{mention .User}} оставляет {{.Stand.Name}} за собой, следующее предупреждение об освобождении не раньше {{.Time.Format \"15:04\"}}",
+		TplAutoReleased:        "{{.Stand.Name}} освобождён автоматически, {{mention .Stand.Owner}} не оставил его за собой",
+		TplAutoReleasedOwner:   "ваш стенд {{.Stand.Name}} освобождён автоматически, вы не оставили его за собой",
+		TplStandBusyBy:         "занят {{mention .Stand.Owner}} уже {{duration .Stand.Held \"д\" \"ч\" \"м\"}}, с {{since .Stand.Claimed \"вчера\" \"02.01 15:04\"}} " + EmojiBusy,
+		TplStandFree:           "свободен " + EmojiFree,
+		TplGreetings:           "Привет, {{mention .User}}! Я StandClaimer бот и помогаю команде делить стенды. Нажми `/` в меню группы, чтобы увидеть команды",
+		TplFeatureState:        "фича: {{.Branch}} {{.State}}",
+		TplDashboardTitle:      "Стенды (обновлено в {{.Time.Format \"15:04\"}})",
+		TplButtonClaim:         "Занять {{.Stand.Name}}",
+		TplButtonRelease:       "Освободить {{.Stand.Name}}",
+		TplChatLanguageSet:     "язык чата: {{.Language}}",
+		TplUserLanguageSet:     "{{mention .User}}, ваш язык: {{.Language}}",
+		TplReminderRelease:     "Освободить {{.Stand.Name}}",
+		TplReminderExtend:      "{{.Stand.Name}} ещё нужен +4ч",
+		TplReminderKeep:        "Оставить {{.Stand.Name}}",
+		TplReminderSnooze:      "Напомнить завтра",
+		TplReminderExtended:    "{{mention .User}} ещё работает на {{.Stand.Name}}, следующее напоминание не раньше {{.Time.Format \"15:04\"}}",
+		TplReminderSnoozed:     "{{mention .User}} отложил напоминания о {{.Stand.Name}} до {{.Time.Format \"02.01 15:04\"}}",
+		TplTransferOffer:       "{{mention .User}}, {{mention .Stand.Owner}} хочет передать тебе {{.Stand.Name}}",
+		TplTransferAccepted:    "{{mention .User}} принял {{.Stand.Name}} от {{mention .Stand.Owner}}",
+		TplTransferDeclined:    "{{mention .User}} не принял {{.Stand.Name}} от {{mention .Stand.Owner}}",
+		TplForceReleased:       "{{mention .User}} принудительно освободил {{.Stand.Name}}{{if .Stand.Owner}} у {{mention .Stand.Owner}}{{end}}: {{.Text}}",
+		TplForceReleasedOwner:  "{{mention .User}} освободил ваш стенд {{.Stand.Name}}: {{.Text}}",
+		TplDigestTitle:         "<b>Стенды на {{.Time.Format \"02.01\"}}</b>",
+		TplDigestFree:          "Свободны " + EmojiFree + ": {{range $i, $s := .Stands}}{{if $i}}, {{end}}{{html $s.Name}}{{end}}",
+		TplMembersSynced:       "Участников синхронизировано: {{len .Users}}{{if .Removed}}, удалены покинувшие чат: {{join .Removed \", \"}}{{end}}",
+		TplSettings:            "настройки @{{.User}}\nнапоминания: {{.Status}}\nтихие часы: {{if .State}}{{.State}}{{else}}нет{{end}}\nязык: {{.Language}}",
+		TplQuietHours:          "Тихо {{.Text}}",
+		TplWorkingHours:        "рабочие часы: {{.Text}}, напоминания и дайджест ждут их",
+		TplConfirmRelease:      "Точно освободить {{.Stand.Name}}?",
+		TplConfirmForceRelease: "Точно принудительно освободить {{.Stand.Name}}? Причина: {{.Text}}",
+		TplConfirmTransfer:     "Точно передать {{.Stand.Name}} {{mention .User}}?",
+		TplMyStand:             "<code>{{pad .Stand.Name .Width | html}}</code> занят уже {{duration .Stand.Held \"д\" \"ч\" \"м\"}}, с {{since .Stand.Claimed \"вчера\" \"02.01 15:04\"}}{{if not .Stand.AckUntil.IsZero}}, напоминания отложены до {{.Stand.AckUntil.Format \"02.01 15:04\"}}{{end}}{{if not .Stand.ReleaseAt.IsZero}}, освободится автоматически в {{.Stand.ReleaseAt.Format \"02.01 15:04\"}}{{end}}",
+		TplRoleSet:             "{{mention .User}}: {{.Text}}",
+		TplDefaultRoleSet:      "роль по умолчанию: {{.Text}}",
+		TplStandAllowed:        "{{mention .User}} может занимать {{.Stand.Name}}",
+		TplStandDisallowed:     "{{mention .User}} больше не может занимать {{.Stand.Name}}",
+		TplStartInGroup:        "напишите мне в личку, чтобы получать напоминания там и пользоваться командами без лишнего шума: t.me/{{.Text}}",
+		TplHelpCommand:         "{{html .Text}} - {{.Status}}",
+
+		CmdStart:         "Пользоваться ботом в личке и получать напоминания там",
+		CmdHelp:          "Показать доступные команды",
+		CmdList:          "Показать все стенды",
+		CmdMyStands:      "Показать ваши стенды с кнопками Освободить/Продлить",
+		CmdClaim:         "Занять стенд",
+		CmdRelease:       "Освободить свой стенд",
+		CmdTransfer:      "Передать свой стенд коллеге",
+		CmdPing:          "Пингануть владельца стенда",
+		CmdPingAll:       "Пингануть владельцев всех занятых стендов",
+		CmdDashboard:     "Закрепить дашборд стендов",
+		CmdFeaturesState: "Показать состояние фич",
+		CmdNotifications: "Выбрать, куда присылать напоминания: в личку или в группу",
+		CmdLanguage:      "Выбрать язык чата, или свой с `me`",
+		CmdForceRelease:  "Освободить чужой стенд с указанием причины",
+		CmdRoles:         "Управлять ролями и допуском к стендам",
+		CmdStandsTopic:   "Присылать напоминания и дашборд в эту тему",
+		CmdSyncMembers:   "Синхронизировать участников чата с телеграмом",
+		CmdWorkingHours:  "Показать или задать рабочие часы для напоминаний",
+		CmdSettings:      "Ваши напоминания, тихие часы и язык",
+	},
+}
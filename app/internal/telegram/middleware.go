@@ -2,24 +2,57 @@ package telegram
 
 import (
 	"errors"
+	"strings"
 
-	"github.com/tibeahx/claimer/pkg/entity"
 	"github.com/tibeahx/claimer/pkg/log"
 	"gopkg.in/telebot.v4"
 )
 
-var ChatInfo = entity.ChatInfo{}
+// TeamChatMiddleware remembers the group chat updates come from, it's
+// stored so workers post to the team chat after restart too
+func TeamChatMiddleware(h *Handler) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			// inline queries and callbacks of inline messages come without chat
+			chat := c.Chat()
+			if chat == nil || (chat.Type != telebot.ChatGroup && chat.Type != telebot.ChatSuperGroup) {
+				return next(c)
+			}
 
-func ChatInfoMiddleware(next telebot.HandlerFunc) telebot.HandlerFunc {
-	return func(c telebot.Context) error {
-		if c.Chat().Type == telebot.ChatGroup || c.Chat().Type == telebot.ChatSuperGroup {
-			ChatInfo.ChatID = c.Chat().ID
+			if h.teamChatID.Swap(chat.ID) != chat.ID {
+				log.Zap().Infof("team chat set to: %d", chat.ID)
+
+				if err := h.repo.SetTeamChat(chat.ID); err != nil {
+					log.Zap().Errorf("failed to store team chat: %v", err)
+				}
+			}
+
+			return next(c)
 		}
-		log.Zap().Infof("chat id set to: %d", ChatInfo.ChatID)
-		return next(c)
 	}
 }
 
+// TeamChatID is the group chat workers post to, 0 if the bot hasn't seen
+// any yet
+func (h *Handler) TeamChatID() int64 {
+	if chatID := h.teamChatID.Load(); chatID != 0 {
+		return chatID
+	}
+
+	chatID, err := h.repo.TeamChat()
+	if err != nil {
+		log.Zap().Errorf("failed to get team chat: %v", err)
+		return 0
+	}
+
+	// an update may have set the chat meanwhile, it's the newer one
+	if !h.teamChatID.CompareAndSwap(0, chatID) {
+		return h.teamChatID.Load()
+	}
+
+	return chatID
+}
+
 var (
 	errNoUsersJoined = errors.New("no users joined within event")
 	errNoUsersLeft   = errors.New("no users left within event")
@@ -34,20 +67,7 @@ func UserJoinedMiddleware(h *Handler) telebot.MiddlewareFunc {
 				return errNoUsersJoined
 			}
 
-			username := msg.UserJoined.Username
-
-			userFound, err := h.repo.FindUser(username)
-			if err != nil {
-				return err
-			}
-
-			if !userFound {
-				if err := h.repo.CreateUser(username); err != nil {
-					return err
-				}
-			} else {
-				log.Zap().Infof("user %s already exists", username)
-			}
+			h.memberJoined(msg.Chat.ID, msg.UserJoined)
 
 			return next(c)
 		}
@@ -63,19 +83,32 @@ func UserLeftMiddleware(h *Handler) telebot.MiddlewareFunc {
 				return errNoUsersLeft
 			}
 
-			username := msg.UserLeft.Username
+			h.memberLeft(msg.UserLeft)
 
-			userFound, err := h.repo.FindUser(username)
+			return next(c)
+		}
+	}
+}
+
+// RoleMiddleware rejects commands the sender's role isn't enough for,
+// callbacks are checked in HandleCallbacks once their data is decoded
+func RoleMiddleware(h *Handler) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			msg := c.Message()
+			if c.Callback() != nil || msg == nil || !strings.HasPrefix(msg.Text, "/") {
+				return next(c)
+			}
+
+			command, _, _ := strings.Cut(strings.Fields(msg.Text)[0], "@")
+
+			allowed, err := h.authorize(c, command)
 			if err != nil {
 				return err
 			}
 
-			if userFound {
-				if err := h.repo.DeleteUser(username); err != nil {
-					return err
-				}
-			} else {
-				log.Zap().Info("user not found to be deleted")
+			if !allowed {
+				return c.Reply(h.tpl(c, ErrRoleRequired, tplData{Text: h.requiredRole(command)}))
 			}
 
 			return next(c)
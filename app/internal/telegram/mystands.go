@@ -0,0 +1,60 @@
+package telegram
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tibeahx/claimer/pkg/entity"
+	"gopkg.in/telebot.v4"
+)
+
+// MyStands shows stands held by the sender with Release and Extend buttons,
+// the buttons are handled by Reminder
+func (h *Handler) MyStands(c telebot.Context) error {
+	stands, err := h.checkStands(c)
+	if err != nil {
+		return err
+	}
+
+	username := c.Sender().Username
+
+	width := 0
+	for _, stand := range stands {
+		if stand.OwnerUsername.String == username {
+			width = max(width, utf8.RuneCountInString(stand.Name))
+		}
+	}
+
+	lines := []string{h.t(c, MsgMyStandsTitle)}
+	menu := make([][]telebot.InlineButton, 0)
+
+	for _, stand := range stands {
+		if stand.Released || stand.OwnerUsername.String != username {
+			continue
+		}
+
+		data := tplData{Stand: newTplStand(stand), Width: width}
+
+		lines = append(lines, h.tpl(c, TplMyStand, data))
+		menu = append(menu, h.myStandButtons(c, data, stand))
+	}
+
+	if len(menu) == 0 {
+		return c.Reply(h.t(c, ErrNoOwnStands))
+	}
+
+	return c.Reply(strings.Join(lines, "\n"), &telebot.ReplyMarkup{InlineKeyboard: menu}, telebot.ModeHTML)
+}
+
+func (h *Handler) myStandButtons(c telebot.Context, data tplData, stand entity.Stand) []telebot.InlineButton {
+	return []telebot.InlineButton{
+		{
+			Text: h.tpl(c, TplReminderRelease, data),
+			Data: h.callbacks.encode(c.Sender().ID, "remind", reminderRelease, stand.Name),
+		},
+		{
+			Text: h.tpl(c, TplReminderExtend, data),
+			Data: h.callbacks.encode(c.Sender().ID, "remind", reminderExtend, stand.Name),
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package telegram
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	notifyPrivate = "private"
+	notifyGroup   = "group"
+)
+
+// TrackUserMiddleware remembers telegram ids of senders, so the bot is able
+// to DM them later, and the group they work in
+func TrackUserMiddleware(h *Handler) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			sender := c.Sender()
+			if sender == nil || sender.Username == "" || sender.IsBot {
+				return next(c)
+			}
+
+			user := entity.User{
+				Username: sender.Username,
+				UserID:   sql.NullInt64{Int64: sender.ID, Valid: true},
+			}
+
+			if chat := c.Chat(); chat != nil && (chat.Type == telebot.ChatGroup || chat.Type == telebot.ChatSuperGroup) {
+				user.TeamChatID = sql.NullInt64{Int64: chat.ID, Valid: true}
+			}
+
+			if err := h.repo.TouchUser(user); err != nil {
+				log.Zap().Errorf("failed to track user %s: %v", sender.Username, err)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Start opens private chat with the bot, all commands work there against
+// the same stands, and user may choose to get reminders privately
+func (h *Handler) Start(c telebot.Context) error {
+	if c.Chat().Type != telebot.ChatPrivate {
+		return c.Reply(h.tpl(c, TplStartInGroup, tplData{Text: h.bot.Tele().Me.Username}))
+	}
+
+	if c.Sender().Username == "" {
+		return c.Reply(h.t(c, ErrNoUsername))
+	}
+
+	if err := h.repo.SetPrivateChat(c.Sender().Username, true); err != nil {
+		return err
+	}
+
+	return c.Reply(h.t(c, MsgStartPrivate), h.notificationsMarkup(c))
+}
+
+// Notifications lets user choose where reminders are delivered
+func (h *Handler) Notifications(c telebot.Context) error {
+	if c.Callback() == nil {
+		return c.Reply(h.t(c, MsgChooseNotifications), h.notificationsMarkup(c))
+	}
+
+	username := c.Sender().Username
+	private := callbackFrom(c).arg(0) == notifyPrivate
+
+	if private {
+		users, err := h.repo.Users([]string{username})
+		if err != nil {
+			return err
+		}
+
+		if len(users) == 0 || !users[0].PrivateChat {
+			return h.answer(c, h.tpl(c, ErrStartBotFirst, tplData{Text: h.bot.Tele().Me.Username}))
+		}
+	}
+
+	if err := h.repo.SetNotifyPrivate(username, private); err != nil {
+		return err
+	}
+
+	if private {
+		return c.Edit(h.t(c, MsgNotifyPrivateSet))
+	}
+
+	return c.Edit(h.t(c, MsgNotifyGroupSet))
+}
+
+func (h *Handler) notificationsMarkup(c telebot.Context) *telebot.ReplyMarkup {
+	menu := createInlineKeyboard([]inlineButton{
+		{
+			text: h.t(c, MsgButtonNotifyPrivate),
+			data: h.callbacks.encode(c.Sender().ID, "notifications", notifyPrivate),
+		},
+		{
+			text: h.t(c, MsgButtonNotifyGroup),
+			data: h.callbacks.encode(c.Sender().ID, "notifications", notifyGroup),
+		},
+	})
+
+	return &telebot.ReplyMarkup{InlineKeyboard: menu}
+}
+
+// sendPrivate DMs user if they opted in for private notifications, false
+// means the caller should fall back to the group chat
+func (h *Handler) sendPrivate(user entity.User, render func(lang i18n.Lang) (string, *telebot.ReplyMarkup)) bool {
+	if !user.NotifyPrivate {
+		return false
+	}
+
+	return h.sendDirect(user, render)
+}
+
+// sendDirect DMs user if they have started the bot, false means the
+// message wasn't delivered
+func (h *Handler) sendDirect(user entity.User, render func(lang i18n.Lang) (string, *telebot.ReplyMarkup)) bool {
+	if !user.PrivateChat || !user.UserID.Valid {
+		return false
+	}
+
+	lang := h.chatLanguage(user.UserID.Int64)
+	if user.Language.Valid {
+		if parsed, ok := i18n.Parse(user.Language.String); ok {
+			lang = parsed
+		}
+	}
+
+	text, markup := render(lang)
+
+	opts := []any{markup}
+	// messages in user's quiet hours come without a sound
+	if h.quiet(user, time.Now()) {
+		opts = append(opts, telebot.Silent)
+	}
+
+	_, err := h.bot.Tele().Send(&telebot.User{ID: user.UserID.Int64}, text, opts...)
+	if err == nil {
+		return true
+	}
+
+	log.Zap().Warnf("failed to DM %s: %v", user.Username, err)
+
+	if errors.Is(err, telebot.ErrBlockedByUser) ||
+		errors.Is(err, telebot.ErrNotStartedByUser) ||
+		errors.Is(err, telebot.ErrUserIsDeactivated) {
+		if err := h.repo.SetPrivateChat(user.Username, false); err != nil {
+			log.Zap().Errorf("failed to disable private chat of %s: %v", user.Username, err)
+		}
+	}
+
+	return false
+}
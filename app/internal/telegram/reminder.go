@@ -0,0 +1,184 @@
+package telegram
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	reminderRelease = "release"
+	reminderExtend  = "extend"
+	reminderSnooze  = "snooze"
+	// cancels automatic release of the stand, the warning comes again
+	// after reminderExtendPeriod
+	reminderKeep = "keep"
+
+	reminderExtendPeriod = 4 * time.Hour
+	// hour of the next day reminders are snoozed till
+	reminderSnoozeHour = 9
+)
+
+// Reminder handles buttons of reminder messages, only owner of the stand
+// may use them, others get a toast
+func (h *Handler) Reminder(c telebot.Context) error {
+	data := callbackFrom(c)
+	op, standName := data.arg(0), data.arg(1)
+	username := c.Sender().Username
+
+	stands, err := h.repo.Stands()
+	if err != nil {
+		return err
+	}
+
+	var stand entity.Stand
+	for _, s := range stands {
+		if s.Name == standName {
+			stand = s
+			break
+		}
+	}
+
+	if stand.Name == "" || stand.Released || stand.OwnerUsername.String != username {
+		return h.toast(c, h.t(c, ErrNotStandOwner))
+	}
+
+	var text string
+
+	switch op {
+	case reminderRelease:
+		if h.needsConfirm(c, "release") {
+			question := h.tpl(c, TplConfirmRelease, tplData{Stand: tplStand{Name: standName}})
+			return h.askConfirm(c, false, question, "remind", reminderRelease, standName)
+		}
+
+		standToRelease := entity.Stand{
+			Name:          standName,
+			OwnerUsername: sql.NullString{String: username},
+		}
+
+		if err := h.repo.ReleaseStand(standToRelease); err != nil {
+			return h.toast(c, h.tpl(c, ErrFailedToRelease, tplData{Err: err.Error()}))
+		}
+
+		h.refreshDashboards()
+
+		text = h.tpl(c, TplStandReleased, tplData{
+			User:  username,
+			Stand: tplStand{Name: standName},
+		})
+	case reminderExtend, reminderSnooze:
+		until := time.Now().Add(reminderExtendPeriod)
+		key := TplReminderExtended
+
+		if op == reminderSnooze {
+			until = tomorrowAt(time.Now(), reminderSnoozeHour)
+			key = TplReminderSnoozed
+		}
+
+		if err := h.repo.AckReminder(stand, until); err != nil {
+			return err
+		}
+
+		text = h.tpl(c, key, tplData{
+			User:  username,
+			Stand: tplStand{Name: standName},
+			Time:  until,
+		})
+	case reminderKeep:
+		until := time.Now().Add(reminderExtendPeriod)
+
+		if err := h.repo.KeepStand(stand, until); err != nil {
+			return err
+		}
+
+		text = h.tpl(c, TplReleaseKept, tplData{
+			User:  username,
+			Stand: tplStand{Name: standName},
+			Time:  until,
+		})
+	default:
+		return nil
+	}
+
+	// confirmed release answers in the confirmation, the reminder keeps
+	// its buttons
+	if confirmed, _ := c.Get(confirmedCtxKey).(bool); confirmed {
+		return c.Edit(text)
+	}
+
+	h.dropReminderButtons(c, standName)
+
+	_, err = h.bot.Tele().Reply(c.Message(), text, messageTopic(c.Message()))
+
+	return err
+}
+
+// reminderMarkup adds a row of buttons for every stand of the reminder,
+// buttons may be pressed by anyone but Reminder checks the owner
+func (h *Handler) reminderMarkup(lang i18n.Lang, stands []entity.Stand) *telebot.ReplyMarkup {
+	menu := make([][]telebot.InlineButton, 0, len(stands))
+
+	for _, stand := range stands {
+		data := tplData{Stand: newTplStand(stand)}
+
+		menu = append(menu, []telebot.InlineButton{
+			{
+				Text: h.text(lang, TplReminderRelease, data),
+				Data: h.callbacks.encode(anyone, "remind", reminderRelease, stand.Name),
+			},
+			{
+				Text: h.text(lang, TplReminderExtend, data),
+				Data: h.callbacks.encode(anyone, "remind", reminderExtend, stand.Name),
+			},
+			{
+				Text: h.text(lang, TplReminderSnooze, data),
+				Data: h.callbacks.encode(anyone, "remind", reminderSnooze, stand.Name),
+			},
+		})
+	}
+
+	return &telebot.ReplyMarkup{InlineKeyboard: menu}
+}
+
+// dropReminderButtons removes row of the stand from the reminder, so
+// buttons of other owners stay usable
+func (h *Handler) dropReminderButtons(c telebot.Context, standName string) {
+	msg := c.Message()
+	if msg == nil || msg.ReplyMarkup == nil {
+		return
+	}
+
+	menu := make([][]telebot.InlineButton, 0, len(msg.ReplyMarkup.InlineKeyboard))
+
+	for _, row := range msg.ReplyMarkup.InlineKeyboard {
+		if len(row) > 0 {
+			data, err := h.callbacks.decode(row[0].Data)
+			if err == nil && data.arg(1) == standName {
+				continue
+			}
+		}
+		menu = append(menu, row)
+	}
+
+	_, err := h.bot.Tele().EditReplyMarkup(msg, &telebot.ReplyMarkup{InlineKeyboard: menu})
+	if err != nil {
+		log.Zap().Warnf("failed to drop reminder buttons: %v", err)
+	}
+}
+
+// toast answers callback with a notification instead of editing the message
+func (h *Handler) toast(c telebot.Context, text string) error {
+	c.Set(respondedCtxKey, true)
+
+	return c.Respond(&telebot.CallbackResponse{Text: text})
+}
+
+func tomorrowAt(now time.Time, hour int) time.Time {
+	y, m, d := now.AddDate(0, 0, 1).Date()
+	return time.Date(y, m, d, hour, 0, 0, 0, now.Location())
+}
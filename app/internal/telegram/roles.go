@@ -0,0 +1,313 @@
+package telegram
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tibeahx/claimer/pkg/entity"
+	"gopkg.in/telebot.v4"
+)
+
+// memberRoleTTL is how long roles telegram reported for chat members are
+// trusted, so a command doesn't cost a request to telegram
+const memberRoleTTL = 5 * time.Minute
+
+type memberKey struct {
+	chatID int64
+	userID int64
+}
+
+type memberRole struct {
+	role    telebot.MemberStatus
+	expires time.Time
+}
+
+// memberRoles caches roles of chat members reported by telegram
+type memberRoles struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	roles map[memberKey]memberRole
+}
+
+func newMemberRoles(ttl time.Duration) *memberRoles {
+	return &memberRoles{
+		ttl:   ttl,
+		roles: make(map[memberKey]memberRole),
+	}
+}
+
+func (m *memberRoles) get(key memberKey, now time.Time) (telebot.MemberStatus, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cached, ok := m.roles[key]
+	if !ok || now.After(cached.expires) {
+		delete(m.roles, key)
+		return "", false
+	}
+
+	return cached.role, true
+}
+
+func (m *memberRoles) set(key memberKey, role telebot.MemberStatus, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, cached := range m.roles {
+		if now.After(cached.expires) {
+			delete(m.roles, k)
+		}
+	}
+
+	m.roles[key] = memberRole{role: role, expires: now.Add(m.ttl)}
+}
+
+// forget drops the cached role, e.g. when chat_member update comes
+func (m *memberRoles) forget(key memberKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.roles, key)
+}
+
+// authorize checks that sender's role is enough for the command or
+// callback action, unknown ones are available to anyone. Telegram is asked
+// whether sender administers the chat only if their stored role isn't
+// enough
+func (h *Handler) authorize(c telebot.Context, name string) (bool, error) {
+	cmd, ok := h.command(name)
+	if !ok {
+		return true, nil
+	}
+
+	role, chat, err := h.storedRole(c)
+	if err != nil {
+		return false, err
+	}
+
+	if entity.RoleAtLeast(role, cmd.role) {
+		return true, nil
+	}
+
+	admin, err := h.isChatAdmin(chat, c.Sender())
+	if err != nil {
+		return false, err
+	}
+
+	return admin && entity.RoleAtLeast(entity.RoleAdmin, cmd.role), nil
+}
+
+// requiredRole is used in replies to those whose role isn't enough
+func (h *Handler) requiredRole(name string) string {
+	cmd, _ := h.command(name)
+	return cmd.role
+}
+
+// role resolves sender's role in the chat, telegram administrators are
+// always admins. In private chats role in user's team chat is used
+func (h *Handler) role(c telebot.Context) (string, error) {
+	role, chat, err := h.storedRole(c)
+	if err != nil || role == entity.RoleAdmin {
+		return role, err
+	}
+
+	admin, err := h.isChatAdmin(chat, c.Sender())
+	if err != nil {
+		return "", err
+	}
+
+	if admin {
+		return entity.RoleAdmin, nil
+	}
+
+	return role, nil
+}
+
+// storedRole returns sender's role given in the chat and the chat, in
+// private chats it's user's team chat or nil if it's unknown
+func (h *Handler) storedRole(c telebot.Context) (string, *telebot.Chat, error) {
+	if c.Sender() == nil {
+		return entity.RoleViewer, nil, nil
+	}
+
+	chat := c.Chat()
+	if chat != nil && chat.Type == telebot.ChatPrivate {
+		chat = nil
+
+		users, err := h.repo.Users([]string{c.Sender().Username})
+		if err != nil {
+			return "", nil, err
+		}
+		if len(users) == 1 && users[0].TeamChatID.Valid {
+			chat = &telebot.Chat{ID: users[0].TeamChatID.Int64}
+		}
+	}
+
+	if chat == nil {
+		return h.defaultRole, nil, nil
+	}
+
+	role, err := h.repo.Role(chat.ID, c.Sender().Username)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !entity.IsRole(role) {
+		return h.defaultRole, chat, nil
+	}
+
+	return role, chat, nil
+}
+
+// isChatAdmin asks telegram whether user administers the chat, answers
+// are cached for memberRoleTTL
+func (h *Handler) isChatAdmin(chat *telebot.Chat, user *telebot.User) (bool, error) {
+	if chat == nil || user == nil || chat.Type == telebot.ChatPrivate {
+		return false, nil
+	}
+
+	var (
+		key = memberKey{chatID: chat.ID, userID: user.ID}
+		now = time.Now()
+	)
+
+	role, ok := h.memberRoles.get(key, now)
+	if !ok {
+		member, err := h.bot.Tele().ChatMemberOf(chat, user)
+		if err != nil {
+			return false, fmt.Errorf("failed to get chat member: %w", err)
+		}
+
+		role = member.Role
+		h.memberRoles.set(key, role, now)
+	}
+
+	return role == telebot.Administrator || role == telebot.Creator, nil
+}
+
+// canClaim checks allowlist of the stand, empty allowlist means anyone
+// may claim the stand
+func (h *Handler) canClaim(standName, username string) (bool, error) {
+	allowed, err := h.repo.StandAllowlist(standName)
+	if err != nil {
+		return false, err
+	}
+
+	return len(allowed) == 0 || slices.Contains(allowed, username), nil
+}
+
+// Roles manages roles of the chat:
+//
+//	/roles
+//	/roles set @user admin|member|viewer
+//	/roles default member|viewer
+//	/roles allow <stand> @user
+//	/roles disallow <stand> @user
+func (h *Handler) Roles(c telebot.Context) error {
+	if c.Chat().Type == telebot.ChatPrivate {
+		return c.Reply(h.t(c, ErrGroupOnly))
+	}
+
+	args := strings.Fields(c.Message().Payload)
+	if len(args) == 0 {
+		return h.listRoles(c)
+	}
+
+	chatID := c.Chat().ID
+
+	switch {
+	case args[0] == "set" && len(args) == 3 && strings.HasPrefix(args[1], "@") && entity.IsRole(args[2]):
+		username := strings.TrimPrefix(args[1], "@")
+
+		err := h.repo.SetRole(entity.ChatRole{
+			ChatID:   chatID,
+			Username: username,
+			Role:     args[2],
+		})
+		if err != nil {
+			return err
+		}
+
+		return c.Reply(h.tpl(c, TplRoleSet, tplData{User: username, Text: args[2]}))
+	case args[0] == "default" && len(args) == 2 && entity.IsRole(args[1]):
+		if err := h.repo.SetDefaultRole(chatID, args[1]); err != nil {
+			return err
+		}
+
+		return c.Reply(h.tpl(c, TplDefaultRoleSet, tplData{Text: args[1]}))
+	case (args[0] == "allow" || args[0] == "disallow") && len(args) >= 3:
+		stands, err := h.checkStands(c)
+		if err != nil {
+			return err
+		}
+
+		standName, rest := splitStandName(stands, strings.Join(args[1:], " "))
+		if standName == "" || !strings.HasPrefix(rest, "@") || strings.Contains(rest, " ") {
+			return c.Reply(h.t(c, ErrRolesUsage))
+		}
+
+		access := entity.StandAccess{
+			StandName: standName,
+			Username:  strings.TrimPrefix(rest, "@"),
+		}
+
+		data := tplData{
+			User:  access.Username,
+			Stand: tplStand{Name: standName},
+		}
+
+		if args[0] == "allow" {
+			if err := h.repo.AllowStand(access); err != nil {
+				return err
+			}
+			return c.Reply(h.tpl(c, TplStandAllowed, data))
+		}
+
+		if err := h.repo.DisallowStand(access); err != nil {
+			return err
+		}
+		return c.Reply(h.tpl(c, TplStandDisallowed, data))
+	default:
+		return c.Reply(h.t(c, ErrRolesUsage))
+	}
+}
+
+func (h *Handler) listRoles(c telebot.Context) error {
+	roles, err := h.repo.Roles(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	access, err := h.repo.Allowlists()
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, len(roles)+len(access)+1)
+
+	defaultRole, err := h.repo.Role(c.Chat().ID, "")
+	if err != nil {
+		return err
+	}
+	if !entity.IsRole(defaultRole) {
+		defaultRole = h.defaultRole
+	}
+
+	lines = append(lines, h.tpl(c, TplDefaultRoleSet, tplData{Text: defaultRole}))
+
+	for _, role := range roles {
+		lines = append(lines, h.tpl(c, TplRoleSet, tplData{User: role.Username, Text: role.Role}))
+	}
+
+	for _, a := range access {
+		lines = append(lines, h.tpl(c, TplStandAllowed, tplData{
+			User:  a.Username,
+			Stand: tplStand{Name: a.StandName},
+		}))
+	}
+
+	return c.Reply(strings.Join(lines, "\n"))
+}
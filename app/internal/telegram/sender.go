@@ -0,0 +1,307 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tibeahx/claimer/pkg/log"
+	"golang.org/x/time/rate"
+)
+
+// limits of telegram for bots: about 30 messages per second overall, one
+// per second in a private chat and 20 per minute in a group
+const (
+	globalSendRate  = 30
+	privateSendRate = rate.Limit(1)
+	groupSendRate   = rate.Limit(20.0 / 60)
+	chatSendBurst   = 3
+
+	sendAttempts       = 4
+	sendBackoff        = 500 * time.Millisecond
+	sendMaxRetryAfter  = time.Minute
+	sendLimiterIdle    = 10 * time.Minute
+	sendHeadersTimeout = time.Minute
+)
+
+// sendTransport is used by the bot's http client. Messages sent or edited
+// by handlers and workers, including c.Send/c.Reply/c.Edit, wait for
+// global and per-chat rate limits, other requests such as callback
+// answers go straight away. 429 responses are retried after retry_after,
+// or with exponential backoff when telegram didn't say how long to wait.
+// Requests that failed before reaching telegram are retried as well, 5xx
+// responses and other network errors only for methods that don't post a
+// new message, so no message is posted twice
+type sendTransport struct {
+	next    http.RoundTripper
+	global  *rate.Limiter
+	backoff time.Duration
+
+	mu     sync.Mutex
+	chats  map[string]*chatLimit
+	pruned time.Time
+}
+
+type chatLimit struct {
+	limiter *rate.Limiter
+	used    time.Time
+}
+
+func newSendTransport(next http.RoundTripper) *sendTransport {
+	return &sendTransport{
+		next:    next,
+		global:  rate.NewLimiter(globalSendRate, globalSendRate),
+		backoff: sendBackoff,
+		chats:   make(map[string]*chatLimit),
+		pruned:  time.Now(),
+	}
+}
+
+// newSendClient builds http client of the bot. There is no overall timeout
+// since waiting for limits and retry_after may take a while, a stuck
+// request is cut by the response headers timeout instead
+func newSendClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = sendHeadersTimeout
+
+	return &http.Client{Transport: newSendTransport(transport)}
+}
+
+// RoundTrip returns the last response or error once a request can't be
+// retried anymore, so callers get the failure as a telebot error. Such
+// failures are also logged as errors here, except 4xx responses which
+// are mistakes of the request itself, e.g. the message is not modified
+func (t *sendTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ctx        = req.Context()
+		method     = path.Base(req.URL.Path)
+		limited    = isMessageMethod(method)
+		idempotent = !isPostingMethod(method)
+		chatID     string
+	)
+
+	if limited {
+		chatID = requestChatID(req, body)
+	}
+
+	var (
+		resp    *http.Response
+		wait    time.Duration
+		retry   bool
+		attempt int
+	)
+
+	for attempt = 1; ; attempt++ {
+		if limited {
+			if err := t.wait(ctx, chatID); err != nil {
+				return nil, err
+			}
+		}
+
+		attemptReq := req.Clone(ctx)
+		attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+
+		resp, err = t.next.RoundTrip(attemptReq)
+
+		wait = t.backoff << (attempt - 1)
+
+		switch {
+		case err != nil:
+			// telegram may have got the request already, repeating it
+			// could post the message twice
+			retry = ctx.Err() == nil && (idempotent || notSent(err))
+		case resp.StatusCode == http.StatusTooManyRequests:
+			retryAfter, data, readErr := readRetryAfter(resp)
+			if readErr != nil {
+				return nil, readErr
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			retry = retryAfter <= sendMaxRetryAfter
+			wait = max(wait, retryAfter)
+		case resp.StatusCode >= http.StatusInternalServerError:
+			retry = idempotent
+		default:
+			return resp, nil
+		}
+
+		if !retry || attempt == sendAttempts {
+			break
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		log.Zap().Warnf("telegram %s to chat %s failed, retrying in %s: %v", method, chatID, wait, describeFailure(resp, err))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	if ctx.Err() == nil {
+		log.Zap().Errorf("telegram %s to chat %s failed after %d attempts: %v", method, chatID, attempt, describeFailure(resp, err))
+	}
+
+	return resp, err
+}
+
+// isMessageMethod reports whether the method sends or edits a message,
+// only those count against rate limits of telegram
+func isMessageMethod(method string) bool {
+	if method == "sendChatAction" {
+		return false
+	}
+
+	return strings.HasPrefix(method, "send") ||
+		strings.HasPrefix(method, "editMessage") ||
+		method == "copyMessage" ||
+		method == "forwardMessage"
+}
+
+// isPostingMethod reports whether repeating the method may post a message
+// twice, e.g. when telegram failed after the message had been sent
+func isPostingMethod(method string) bool {
+	if method == "sendChatAction" {
+		return false
+	}
+
+	return strings.HasPrefix(method, "send") ||
+		strings.HasPrefix(method, "copyMessage") ||
+		strings.HasPrefix(method, "forwardMessage")
+}
+
+// notSent reports whether the request failed before it was sent, e.g.
+// connection or name resolution failed, so it's safe to retry
+func notSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// wait blocks until both global limit and limit of the chat allow a request
+func (t *sendTransport) wait(ctx context.Context, chatID string) error {
+	if err := t.global.Wait(ctx); err != nil {
+		return err
+	}
+
+	if chatID == "" {
+		return nil
+	}
+
+	return t.chatLimiter(chatID).Wait(ctx)
+}
+
+func (t *sendTransport) chatLimiter(chatID string) *rate.Limiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.prune(now)
+
+	chat, ok := t.chats[chatID]
+	if !ok {
+		limit := privateSendRate
+		if strings.HasPrefix(chatID, "-") {
+			limit = groupSendRate
+		}
+
+		chat = &chatLimit{limiter: rate.NewLimiter(limit, chatSendBurst)}
+		t.chats[chatID] = chat
+	}
+	chat.used = now
+
+	return chat.limiter
+}
+
+// prune forgets limiters of chats idle for a while, their buckets are full
+// again by then so a new limiter behaves the same. Called with mu held
+func (t *sendTransport) prune(now time.Time) {
+	if now.Sub(t.pruned) < sendLimiterIdle {
+		return
+	}
+	t.pruned = now
+
+	for chatID, chat := range t.chats {
+		if now.Sub(chat.used) >= sendLimiterIdle {
+			delete(t.chats, chatID)
+		}
+	}
+}
+
+// requestChatID gets chat_id of json requests, requests with files are
+// only limited globally
+func requestChatID(req *http.Request, body []byte) string {
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		return ""
+	}
+
+	var params struct {
+		ChatID json.RawMessage `json:"chat_id"`
+	}
+
+	if err := json.Unmarshal(body, &params); err != nil {
+		return ""
+	}
+
+	return strings.Trim(string(params.ChatID), `"`)
+}
+
+// readRetryAfter reads body of 429 response, returned data is used to
+// restore the body for the caller
+func readRetryAfter(resp *http.Response) (time.Duration, []byte, error) {
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read telegram response: %w", err)
+	}
+
+	var result struct {
+		Parameters struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return 0, data, nil
+	}
+
+	return time.Duration(result.Parameters.RetryAfter) * time.Second, data, nil
+}
+
+func describeFailure(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if resp != nil {
+		return errors.New(resp.Status)
+	}
+
+	return nil
+}
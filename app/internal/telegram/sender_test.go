@@ -0,0 +1,149 @@
+package telegram
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestChatID(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{name: "group", contentType: "application/json", body: `{"chat_id":-100123,"text":"hi"}`, want: "-100123"},
+		{name: "private", contentType: "application/json", body: `{"chat_id":42}`, want: "42"},
+		{name: "channel username", contentType: "application/json", body: `{"chat_id":"@stands"}`, want: "@stands"},
+		{name: "no chat", contentType: "application/json", body: `{"callback_query_id":"1"}`, want: ""},
+		{name: "multipart", contentType: "multipart/form-data; boundary=x", body: `{"chat_id":42}`, want: ""},
+		{name: "broken json", contentType: "application/json", body: `{"chat_id":`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "https://api.telegram.org/bot1/sendMessage", nil)
+			req.Header.Set("Content-Type", tt.contentType)
+
+			if got := requestChatID(req, []byte(tt.body)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want time.Duration
+	}{
+		{name: "retry after", body: `{"ok":false,"error_code":429,"parameters":{"retry_after":7}}`, want: 7 * time.Second},
+		{name: "no parameters", body: `{"ok":false,"error_code":429}`, want: 0},
+		{name: "not json", body: `Too Many Requests`, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Body: io.NopCloser(strings.NewReader(tt.body))}
+
+			got, data, err := readRetryAfter(resp)
+			if err != nil {
+				t.Fatalf("readRetryAfter failed: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+
+			// the body is given back to be restored for the caller
+			if string(data) != tt.body {
+				t.Errorf("got body %q, want %q", data, tt.body)
+			}
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestSendTransportRetries(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+		body   string
+		err    error
+		want   int
+	}{
+		{name: "send on 5xx", method: "sendMessage", status: http.StatusBadGateway, want: 1},
+		{name: "edit on 5xx", method: "editMessageText", status: http.StatusBadGateway, want: sendAttempts},
+		{name: "callback answer on 5xx", method: "answerCallbackQuery", status: http.StatusInternalServerError, want: sendAttempts},
+		{name: "send on 429 without retry_after", method: "sendMessage", status: http.StatusTooManyRequests, body: `{"ok":false}`, want: sendAttempts},
+		{name: "send on 429 with long retry_after", method: "sendMessage", status: http.StatusTooManyRequests, body: `{"parameters":{"retry_after":3600}}`, want: 1},
+		{name: "send on network error", method: "sendMessage", err: errors.New("connection reset"), want: 1},
+		{name: "delete on network error", method: "deleteMessage", err: errors.New("connection reset"), want: sendAttempts},
+		{name: "bad request", method: "editMessageText", status: http.StatusBadRequest, want: 1},
+		{name: "ok", method: "sendMessage", status: http.StatusOK, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+
+			transport := newSendTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+				calls++
+				if tt.err != nil {
+					return nil, tt.err
+				}
+
+				return &http.Response{
+					StatusCode: tt.status,
+					Status:     http.StatusText(tt.status),
+					Body:       io.NopCloser(strings.NewReader(tt.body)),
+				}, nil
+			}))
+			transport.backoff = time.Millisecond
+
+			req, _ := http.NewRequest(http.MethodPost, "https://api.telegram.org/bot1/"+tt.method, strings.NewReader("{}"))
+
+			resp, err := transport.RoundTrip(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			if calls != tt.want {
+				t.Errorf("got %d attempts, want %d", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendTransportPrune(t *testing.T) {
+	transport := newSendTransport(http.DefaultTransport)
+
+	transport.chatLimiter("1")
+	transport.chatLimiter("-2")
+
+	transport.mu.Lock()
+	transport.chats["1"].used = time.Now().Add(-sendLimiterIdle)
+	transport.pruned = time.Now().Add(-sendLimiterIdle)
+	transport.mu.Unlock()
+
+	transport.chatLimiter("3")
+
+	if _, ok := transport.chats["1"]; ok {
+		t.Error("idle chat is not pruned")
+	}
+
+	if _, ok := transport.chats["-2"]; !ok {
+		t.Error("active chat is pruned")
+	}
+}
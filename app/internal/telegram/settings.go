@@ -0,0 +1,198 @@
+package telegram
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/i18n"
+	"github.com/tibeahx/claimer/app/internal/workhours"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	settingNotify   = "notify"
+	settingQuiet    = "quiet"
+	settingLanguage = "language"
+
+	quietHoursOff = "off"
+)
+
+// quiet hours user may choose as HH:MM-HH:MM, they may span midnight
+var quietHoursOptions = []string{"22:00-08:00", "20:00-09:00"}
+
+// Settings shows preferences of the sender with buttons to change them:
+// where reminders come, quiet hours and language
+func (h *Handler) Settings(c telebot.Context) error {
+	user, err := h.sender(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Callback() == nil {
+		return h.sendMenu(c, h.settingsText(h.lang(c), user), h.settingsMarkup(c, h.lang(c), user))
+	}
+
+	data := callbackFrom(c)
+	lang := h.lang(c)
+
+	switch value := data.arg(1); data.arg(0) {
+	case settingNotify:
+		private := value == notifyPrivate
+		if private && !user.PrivateChat {
+			return h.toast(c, h.tpl(c, ErrStartBotFirst, tplData{Text: h.bot.Tele().Me.Username}))
+		}
+		err = h.repo.SetNotifyPrivate(user.Username, private)
+	case settingQuiet:
+		quiet := ""
+		if value != quietHoursOff {
+			quiet = value
+			// hours are kept in the time zone of user's team
+			if loc := h.userLocation(user); loc != time.Local {
+				quiet += " " + loc.String()
+			}
+		}
+		err = h.repo.SetQuietHours(user.Username, quiet)
+	case settingLanguage:
+		parsed, ok := i18n.Parse(value)
+		if !ok {
+			return nil
+		}
+		lang = parsed
+		err = h.repo.SetUserLanguage(user.Username, string(lang))
+		h.languages.reset()
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if user, err = h.sender(c); err != nil {
+		return err
+	}
+
+	if err := c.Edit(h.settingsText(lang, user), h.settingsMarkup(c, lang, user)); err != nil {
+		return err
+	}
+
+	// the menu stays open for other settings
+	return h.toast(c, h.text(lang, MsgSettingsSaved, nil))
+}
+
+// sender returns stored user of the update
+func (h *Handler) sender(c telebot.Context) (entity.User, error) {
+	username := c.Sender().Username
+
+	users, err := h.repo.Users([]string{username})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	if len(users) == 0 {
+		return entity.User{Username: username}, nil
+	}
+
+	return users[0], nil
+}
+
+func (h *Handler) settingsText(lang i18n.Lang, user entity.User) string {
+	data := tplData{
+		User:     user.Username,
+		Status:   h.text(lang, MsgButtonNotifyGroup, nil),
+		State:    user.QuietHours.String,
+		Language: h.text(lang, MsgLanguageName, nil),
+	}
+
+	if user.NotifyPrivate {
+		data.Status = h.text(lang, MsgButtonNotifyPrivate, nil)
+	}
+
+	return h.text(lang, TplSettings, data)
+}
+
+func (h *Handler) settingsMarkup(c telebot.Context, lang i18n.Lang, user entity.User) *telebot.ReplyMarkup {
+	owner := c.Sender().ID
+
+	button := func(text string, selected bool, setting, value string) inlineButton {
+		if selected {
+			text = EmojiSelected + " " + text
+		}
+		return inlineButton{
+			text: text,
+			data: h.callbacks.encode(owner, "settings", setting, value),
+		}
+	}
+
+	notify := []inlineButton{
+		button(h.text(lang, MsgButtonNotifyPrivate, nil), user.NotifyPrivate, settingNotify, notifyPrivate),
+		button(h.text(lang, MsgButtonNotifyGroup, nil), !user.NotifyPrivate, settingNotify, notifyGroup),
+	}
+
+	quiet := []inlineButton{
+		button(h.text(lang, MsgQuietHoursOff, nil), !user.QuietHours.Valid, settingQuiet, quietHoursOff),
+	}
+	for _, hours := range quietHoursOptions {
+		quiet = append(quiet, button(
+			h.text(lang, TplQuietHours, tplData{Text: hours}),
+			strings.HasPrefix(user.QuietHours.String, hours),
+			settingQuiet,
+			hours,
+		))
+	}
+
+	languages := make([]inlineButton, 0, len(i18n.Supported()))
+	for _, option := range i18n.Supported() {
+		languages = append(languages, button(
+			h.text(option, MsgLanguageName, nil),
+			option == lang,
+			settingLanguage,
+			string(option),
+		))
+	}
+
+	menu := make([][]telebot.InlineButton, 0)
+	for _, section := range [][]inlineButton{notify, quiet, languages} {
+		menu = append(menu, createInlineKeyboard(section)...)
+	}
+
+	return &telebot.ReplyMarkup{InlineKeyboard: menu}
+}
+
+// userLocation is time zone of user's team, local one if it's unknown
+func (h *Handler) userLocation(user entity.User) *time.Location {
+	if hours := h.ChatHours(user.TeamChatID.Int64); hours.Location != nil {
+		return hours.Location
+	}
+
+	return time.Local
+}
+
+// quiet reports whether user asked not to be disturbed at the time
+func (h *Handler) quiet(user entity.User, now time.Time) bool {
+	if !user.QuietHours.Valid {
+		return false
+	}
+
+	// same-day quiet hours such as 13:00-14:00 are plain hours
+	if hours, err := workhours.Parse(user.QuietHours.String); err == nil {
+		return hours.Open(now)
+	}
+
+	// quiet hours spanning midnight end before they start, so awake hours
+	// between their end and start are checked instead
+	fields := strings.Fields(user.QuietHours.String)
+	if len(fields) == 0 {
+		return false
+	}
+
+	from, to, _ := strings.Cut(fields[0], "-")
+	fields[0] = to + "-" + from
+
+	awake, err := workhours.Parse(strings.Join(fields, " "))
+	if err != nil {
+		return false
+	}
+
+	return !awake.Open(now)
+}
@@ -0,0 +1,48 @@
+package telegram
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/tibeahx/claimer/pkg/entity"
+)
+
+func TestQuietHours(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 5, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		quiet string
+		t     time.Time
+		want  bool
+	}{
+		{name: "off", quiet: "", t: at(6, 23, 0), want: false},
+		{name: "evening", quiet: "22:00-08:00 UTC", t: at(6, 23, 0), want: true},
+		{name: "start", quiet: "22:00-08:00 UTC", t: at(6, 22, 0), want: true},
+		{name: "after midnight", quiet: "22:00-08:00 UTC", t: at(7, 3, 0), want: true},
+		{name: "end", quiet: "22:00-08:00 UTC", t: at(7, 8, 0), want: false},
+		{name: "day", quiet: "22:00-08:00 UTC", t: at(7, 12, 0), want: false},
+		{name: "team time zone", quiet: "22:00-08:00 Etc/GMT-3", t: at(7, 4, 30), want: true},
+		{name: "same day", quiet: "13:00-14:00 UTC", t: at(7, 13, 30), want: true},
+		{name: "same day start", quiet: "13:00-14:00 UTC", t: at(7, 13, 0), want: true},
+		{name: "same day end", quiet: "13:00-14:00 UTC", t: at(7, 14, 0), want: false},
+		{name: "same day before", quiet: "13:00-14:00 UTC", t: at(7, 12, 59), want: false},
+		{name: "same day night", quiet: "13:00-14:00 UTC", t: at(7, 3, 0), want: false},
+		{name: "invalid", quiet: "late", t: at(7, 3, 0), want: false},
+	}
+
+	h := &Handler{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := entity.User{QuietHours: sql.NullString{String: tt.quiet, Valid: tt.quiet != ""}}
+
+			if got := h.quiet(user, tt.t); got != tt.want {
+				t.Errorf("quiet(%q, %s) = %t, want %t", tt.quiet, tt.t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package telegram
+
+import (
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+const standsTopicOff = "off"
+
+// StandsTopic makes the forum topic it's sent in the one for reminders and
+// the dashboard, `/stands_topic off` brings them back to the General topic
+func (h *Handler) StandsTopic(c telebot.Context) error {
+	threadID := 0
+
+	if c.Message().Payload != standsTopicOff {
+		if !c.Message().TopicMessage {
+			return c.Reply(h.t(c, ErrNotInTopic))
+		}
+		threadID = c.Message().ThreadID
+	}
+
+	if err := h.repo.SetStandsThread(c.Chat().ID, threadID); err != nil {
+		return err
+	}
+
+	if threadID == 0 {
+		return c.Reply(h.t(c, MsgStandsTopicReset))
+	}
+
+	return c.Reply(h.t(c, MsgStandsTopicSet))
+}
+
+// standsTopic is the topic messages the bot sends on its own go to: the
+// configured one, otherwise the General topic
+func (h *Handler) standsTopic(chatID int64) *telebot.Topic {
+	threadID, err := h.repo.StandsThread(chatID)
+	if err != nil {
+		log.Zap().Errorf("failed to get stands topic of chat %d: %v", chatID, err)
+	}
+
+	return &telebot.Topic{ThreadID: threadID}
+}
+
+// messageTopic keeps replies sent by the bot itself in the topic of msg
+func messageTopic(msg *telebot.Message) *telebot.Topic {
+	if msg == nil || !msg.TopicMessage {
+		return &telebot.Topic{}
+	}
+
+	return &telebot.Topic{ThreadID: msg.ThreadID}
+}
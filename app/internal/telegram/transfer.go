@@ -0,0 +1,193 @@
+package telegram
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/tibeahx/claimer/app/internal/repo"
+	"github.com/tibeahx/claimer/pkg/entity"
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	transferAccept  = "accept"
+	transferDecline = "decline"
+	// confirmed offer, args are stand name and recipient
+	transferOffer = "offer"
+)
+
+// Transfer offers sender's stand to another user with `/transfer dev @user`,
+// ownership changes only when recipient accepts
+func (h *Handler) Transfer(c telebot.Context) error {
+	if c.Callback() != nil {
+		data := callbackFrom(c)
+		if data.arg(0) == transferOffer {
+			return h.offerTransfer(c, data.arg(1), data.arg(2))
+		}
+		return h.resolveTransfer(c)
+	}
+
+	args := strings.Fields(c.Message().Payload)
+	if len(args) < 2 || !strings.HasPrefix(args[len(args)-1], "@") {
+		return c.Reply(h.t(c, ErrTransferUsage))
+	}
+
+	var (
+		standName = strings.Join(args[:len(args)-1], " ")
+		to        = strings.TrimPrefix(args[len(args)-1], "@")
+	)
+
+	if to == c.Sender().Username || to == "" {
+		return c.Reply(h.t(c, ErrTransferUsage))
+	}
+
+	if h.needsConfirm(c, "transfer") {
+		question := h.tpl(c, TplConfirmTransfer, tplData{
+			User:  to,
+			Stand: tplStand{Name: standName},
+		})
+		return h.askConfirm(c, false, question, "transfer", transferOffer, standName, to)
+	}
+
+	return h.offerTransfer(c, standName, to)
+}
+
+func (h *Handler) offerTransfer(c telebot.Context, standName, to string) error {
+	from := c.Sender().Username
+
+	stands, err := h.checkStands(c)
+	if err != nil {
+		return err
+	}
+
+	owned := false
+	for _, stand := range stands {
+		if stand.Name == standName && !stand.Released && stand.OwnerUsername.String == from {
+			owned = true
+			break
+		}
+	}
+
+	if !owned {
+		return h.respond(c, h.t(c, ErrNotStandOwner))
+	}
+
+	// transfer is a claim by recipient, so allowlist of the stand applies
+	allowed, err := h.canClaim(standName, to)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return h.respond(c, h.tpl(c, ErrStandNotAllowed, tplData{User: to}))
+	}
+
+	if err := h.repo.CreateUser(to); err != nil {
+		return h.respond(c, h.tpl(c, ErrFailedToAddUser, tplData{Err: err.Error()}))
+	}
+
+	id, err := h.repo.CreateTransfer(entity.Transfer{
+		StandName:    standName,
+		FromUsername: from,
+		ToUsername:   to,
+	})
+	if err != nil {
+		return err
+	}
+
+	// menu is bound to recipient if the bot has seen them already,
+	// otherwise resolveTransfer checks the username
+	owner := anyone
+	users, err := h.repo.Users([]string{to})
+	if err != nil {
+		return err
+	}
+	if len(users) == 1 && users[0].UserID.Valid {
+		owner = users[0].UserID.Int64
+	}
+
+	transferID := strconv.FormatInt(id, 10)
+
+	menu := createInlineKeyboard([]inlineButton{
+		{
+			text: h.t(c, MsgButtonAccept),
+			data: h.callbacks.encode(owner, "transfer", transferAccept, transferID),
+		},
+		{
+			text: h.t(c, MsgButtonDecline),
+			data: h.callbacks.encode(owner, "transfer", transferDecline, transferID),
+		},
+	})
+
+	offer := h.tpl(c, TplTransferOffer, tplData{
+		User:  to,
+		Stand: tplStand{Name: standName, Owner: from},
+	})
+
+	// confirmed offer replaces the confirmation
+	if c.Callback() != nil {
+		return c.Edit(offer, &telebot.ReplyMarkup{InlineKeyboard: menu})
+	}
+
+	return c.Send(offer, &telebot.ReplyMarkup{InlineKeyboard: menu})
+}
+
+func (h *Handler) resolveTransfer(c telebot.Context) error {
+	data := callbackFrom(c)
+
+	id, err := strconv.ParseInt(data.arg(1), 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	transfer, err := h.repo.Transfer(id)
+	if err != nil {
+		return err
+	}
+
+	if transfer.ToUsername != c.Sender().Username {
+		return h.toast(c, h.t(c, ErrMenuNotYours))
+	}
+
+	if transfer.Status != entity.TransferPending {
+		return h.answer(c, h.t(c, ErrButtonExpired))
+	}
+
+	tpl := tplData{
+		User:  transfer.ToUsername,
+		Stand: tplStand{Name: transfer.StandName, Owner: transfer.FromUsername},
+	}
+
+	if data.arg(0) == transferDecline {
+		if err := h.repo.DeclineTransfer(id); err != nil {
+			return err
+		}
+		return h.answer(c, h.tpl(c, TplTransferDeclined, tpl))
+	}
+
+	// allowlist may have changed since the offer
+	allowed, err := h.canClaim(transfer.StandName, transfer.ToUsername)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		// the offer can't be accepted anymore, so the stand isn't held by it
+		if err := h.repo.DeclineTransfer(id); err != nil {
+			return err
+		}
+		return h.answer(c, h.tpl(c, ErrStandNotAllowed, tplData{User: transfer.ToUsername}))
+	}
+
+	if err := h.repo.AcceptTransfer(transfer); err != nil {
+		if errors.Is(err, repo.ErrStandNotOwned) {
+			return h.answer(c, h.tpl(c, ErrTransferStale, tpl))
+		}
+		return h.answer(c, h.tpl(c, ErrFailedToTransfer, tplData{Err: err.Error()}))
+	}
+
+	h.refreshDashboards()
+
+	return h.answer(c, h.tpl(c, TplTransferAccepted, tpl))
+}
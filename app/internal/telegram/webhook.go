@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/config"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	webhookSecretHeader    = "X-Telegram-Bot-Api-Secret-Token"
+	webhookShutdownTimeout = 5 * time.Second
+)
+
+// webhookPoller receives updates over HTTP, it registers the webhook with
+// the secret token and serves it until the bot is stopped. TLS is
+// terminated by the listener if cert and key are set, otherwise it's
+// expected to be done by a reverse proxy in front of the bot
+type webhookPoller struct {
+	cfg  config.WebhookConfig
+	path string
+}
+
+func newWebhookPoller(cfg config.WebhookConfig) (*webhookPoller, error) {
+	publicURL, err := url.Parse(cfg.PublicURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook url: %w", err)
+	}
+
+	path := publicURL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return &webhookPoller{
+		cfg:  cfg,
+		path: path,
+	}, nil
+}
+
+func (p *webhookPoller) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	hook := &telebot.Webhook{
+		AllowedUpdates: allowedUpdates,
+		SecretToken:    p.cfg.Secret,
+		Endpoint:       &telebot.WebhookEndpoint{PublicURL: p.cfg.PublicURL},
+	}
+
+	if p.cfg.UploadCert {
+		hook.Endpoint.Cert = p.cfg.TLSCert
+	}
+
+	if err := b.SetWebhook(hook); err != nil {
+		log.Zap().Fatalf("failed to set webhook: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(p.path, p.handler(dest))
+
+	srv := &http.Server{
+		Addr:              p.cfg.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: pollerTimeout,
+	}
+
+	go func() {
+		<-stop
+
+		ctx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Zap().Errorf("failed to shutdown webhook listener: %v", err)
+		}
+	}()
+
+	var err error
+	if p.cfg.TLSCert != "" && p.cfg.TLSKey != "" {
+		err = srv.ListenAndServeTLS(p.cfg.TLSCert, p.cfg.TLSKey)
+	} else {
+		err = srv.ListenAndServe()
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Zap().Fatalf("webhook listener failed: %v", err)
+	}
+}
+
+// handler accepts updates only from telegram, which sends the secret
+// token registered with the webhook in every request
+func (p *webhookPoller) handler(dest chan<- telebot.Update) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		secret := r.Header.Get(webhookSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(p.cfg.Secret)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var update telebot.Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case dest <- update:
+		case <-r.Context().Done():
+		}
+	}
+}
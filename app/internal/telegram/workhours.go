@@ -0,0 +1,94 @@
+package telegram
+
+import (
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/workhours"
+	"github.com/tibeahx/claimer/pkg/log"
+	"gopkg.in/telebot.v4"
+)
+
+const (
+	// reminders are sent any time whatever is configured
+	workingHoursOff = "off"
+	// configured working hours are used
+	workingHoursReset = "reset"
+)
+
+// WorkingHours shows or sets working hours of the chat, reminders and the
+// digest wait for them:
+//
+//	/working_hours
+//	/working_hours 09:00-18:00 [mon,tue,wed,thu,fri] [Europe/Moscow]
+//	/working_hours off|reset
+func (h *Handler) WorkingHours(c telebot.Context) error {
+	if c.Chat().Type == telebot.ChatPrivate {
+		return c.Reply(h.t(c, ErrGroupOnly))
+	}
+
+	chatID := c.Chat().ID
+	payload := c.Message().Payload
+
+	switch payload {
+	case "":
+	case workingHoursOff:
+		if err := h.repo.SetWorkingHours(chatID, workingHoursOff); err != nil {
+			return err
+		}
+	case workingHoursReset:
+		if err := h.repo.SetWorkingHours(chatID, ""); err != nil {
+			return err
+		}
+	default:
+		hours, err := workhours.Parse(payload)
+		if err != nil {
+			return c.Reply(h.tpl(c, ErrWorkingHoursUsage, tplData{Err: err.Error()}))
+		}
+
+		if err := h.repo.SetWorkingHours(chatID, hours.String()); err != nil {
+			return err
+		}
+	}
+
+	hours := h.ChatHours(chatID)
+	if hours.IsZero() {
+		return c.Reply(h.t(c, MsgWorkingHoursAnyTime))
+	}
+
+	return c.Reply(h.tpl(c, TplWorkingHours, tplData{Text: hours.String()}))
+}
+
+// ChatHours returns working hours of the chat, configured ones if the
+// chat has none
+func (h *Handler) ChatHours(chatID int64) workhours.Hours {
+	spec, err := h.repo.WorkingHours(chatID)
+	if err != nil {
+		log.Zap().Errorf("failed to get working hours of chat %d: %v", chatID, err)
+		return h.workingHours
+	}
+
+	switch spec {
+	case "":
+		return h.workingHours
+	case workingHoursOff:
+		return workhours.Hours{}
+	}
+
+	hours, err := workhours.Parse(spec)
+	if err != nil {
+		log.Zap().Errorf("invalid working hours of chat %d: %v", chatID, err)
+		return h.workingHours
+	}
+
+	return hours
+}
+
+// Held returns how long the stand claimed at since is held by now, only
+// working hours of the chat count if it's configured so
+func (h *Handler) Held(chatID int64, since, now time.Time) time.Duration {
+	if !h.countHeld {
+		return now.Sub(since)
+	}
+
+	return h.ChatHours(chatID).Working(since, now)
+}
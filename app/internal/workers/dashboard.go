@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/telegram"
+	"github.com/tibeahx/claimer/pkg/log"
+)
+
+type DashboardRefresher struct {
+	handler *telegram.Handler
+	stopCh  chan struct{}
+}
+
+func NewDashboardRefresher(handler *telegram.Handler) *DashboardRefresher {
+	return &DashboardRefresher{
+		handler: handler,
+		stopCh:  make(chan struct{}, 1),
+	}
+}
+
+func (w *DashboardRefresher) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.WithSource(log.Zap().Desugar(), "dashboard").Info("shut down")
+			return
+		case <-w.stopCh:
+			log.WithSource(log.Zap().Desugar(), "dashboard").Info("received stop signal")
+			return
+		case <-ticker.C:
+			if err := w.handler.RefreshDashboards(); err != nil {
+				log.WithSource(log.Zap().Desugar(), "dashboard").
+					Sugar().
+					Errorf("refresh failed in worker due to %v", err)
+				continue
+			}
+		}
+	}
+}
+
+func (w *DashboardRefresher) Stop() {
+	w.stopCh <- struct{}{}
+	close(w.stopCh)
+	<-w.stopCh
+}
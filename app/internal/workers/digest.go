@@ -0,0 +1,116 @@
+package workers
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/config"
+	"github.com/tibeahx/claimer/app/internal/telegram"
+	"github.com/tibeahx/claimer/pkg/log"
+)
+
+// Digest posts stands overview to the team chat every scheduled day
+type Digest struct {
+	handler  *telegram.Handler
+	schedule config.DigestConfig
+	stopCh   chan struct{}
+}
+
+func NewDigest(handler *telegram.Handler, schedule config.DigestConfig) *Digest {
+	return &Digest{
+		handler:  handler,
+		schedule: schedule,
+		stopCh:   make(chan struct{}, 1),
+	}
+}
+
+func (w *Digest) Start(ctx context.Context) {
+	var (
+		at time.Time
+		// day the last digest was posted, a deferred digest isn't
+		// posted twice that day
+		posted time.Time
+	)
+
+	for {
+		if at.IsZero() {
+			at = w.next(time.Now())
+			if sameDay(at, posted) {
+				at = w.next(at)
+			}
+		}
+
+		timer := time.NewTimer(time.Until(at))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.WithSource(log.Zap().Desugar(), "digest").Info("shut down")
+			return
+		case <-w.stopCh:
+			timer.Stop()
+			log.WithSource(log.Zap().Desugar(), "digest").Info("received stop signal")
+			return
+		case <-timer.C:
+		}
+
+		chatID := w.handler.TeamChatID()
+		now := time.Now()
+
+		// the bot hasn't been added to the team chat yet
+		if chatID == 0 {
+			at = time.Time{}
+			continue
+		}
+
+		// the digest waits for working hours of the chat
+		if next := w.handler.ChatHours(chatID).Next(now); next.After(now) {
+			log.WithSource(log.Zap().Desugar(), "digest").
+				Sugar().
+				Infof("digest is deferred till %s", next.Format(time.RFC3339))
+			at = next
+			continue
+		}
+
+		at, posted = time.Time{}, now.In(w.schedule.Location)
+
+		if err := w.handler.Digest(chatID); err != nil {
+			log.WithSource(log.Zap().Desugar(), "digest").
+				Sugar().
+				Errorf("digest failed in worker due to %v", err)
+		}
+	}
+}
+
+// next returns the first scheduled time after now
+func (w *Digest) next(now time.Time) time.Time {
+	now = now.In(w.schedule.Location)
+
+	y, m, d := now.Date()
+	next := time.Date(y, m, d, w.schedule.Hour, w.schedule.Minute, 0, 0, w.schedule.Location)
+
+	for !next.After(now) || !w.scheduled(next.Weekday()) {
+		y, m, d = next.AddDate(0, 0, 1).Date()
+		next = time.Date(y, m, d, w.schedule.Hour, w.schedule.Minute, 0, 0, w.schedule.Location)
+	}
+
+	return next
+}
+
+func (w *Digest) scheduled(day time.Weekday) bool {
+	return len(w.schedule.Days) == 0 || slices.Contains(w.schedule.Days, day)
+}
+
+func sameDay(a, b time.Time) bool {
+	ya, ma, da := a.Date()
+	yb, mb, db := b.Date()
+
+	return ya == yb && ma == mb && da == db
+}
+
+func (w *Digest) Stop() {
+	w.stopCh <- struct{}{}
+	close(w.stopCh)
+	<-w.stopCh
+}
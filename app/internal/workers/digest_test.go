@@ -0,0 +1,48 @@
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/config"
+)
+
+func TestDigestNext(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*3600)
+
+	// 2024-05-06 is Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 5, day, hour, minute, 0, 0, msk)
+	}
+
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	tests := []struct {
+		name string
+		days []time.Weekday
+		now  time.Time
+		want time.Time
+	}{
+		{name: "later today", now: at(6, 8, 0), want: at(6, 10, 0)},
+		{name: "exactly now", now: at(6, 10, 0), want: at(7, 10, 0)},
+		{name: "passed today", now: at(6, 12, 0), want: at(7, 10, 0)},
+		{name: "friday evening", days: weekdays, now: at(10, 12, 0), want: at(13, 10, 0)},
+		{name: "saturday", days: weekdays, now: at(11, 8, 0), want: at(13, 10, 0)},
+		{name: "once a week", days: []time.Weekday{time.Wednesday}, now: at(8, 10, 30), want: at(15, 10, 0)},
+		{name: "other time zone", now: time.Date(2024, 5, 6, 6, 30, 0, 0, time.UTC), want: at(6, 10, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Digest{schedule: config.DigestConfig{
+				Hour:     10,
+				Location: msk,
+				Days:     tt.days,
+			}}
+
+			if got := w.next(tt.now); !got.Equal(tt.want) {
+				t.Errorf("next(%s) = %s, want %s", tt.now, got, tt.want)
+			}
+		})
+	}
+}
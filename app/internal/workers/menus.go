@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/telegram"
+	"github.com/tibeahx/claimer/pkg/log"
+)
+
+type MenuJanitor struct {
+	handler *telegram.Handler
+	stopCh  chan struct{}
+}
+
+func NewMenuJanitor(handler *telegram.Handler) *MenuJanitor {
+	return &MenuJanitor{
+		handler: handler,
+		stopCh:  make(chan struct{}, 1),
+	}
+}
+
+func (w *MenuJanitor) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.WithSource(log.Zap().Desugar(), "menus").Info("shut down")
+			return
+		case <-w.stopCh:
+			log.WithSource(log.Zap().Desugar(), "menus").Info("received stop signal")
+			return
+		case <-ticker.C:
+			if err := w.handler.CleanupMenus(); err != nil {
+				log.WithSource(log.Zap().Desugar(), "menus").
+					Sugar().
+					Errorf("cleanup failed in worker due to %v", err)
+				continue
+			}
+		}
+	}
+}
+
+func (w *MenuJanitor) Stop() {
+	w.stopCh <- struct{}{}
+	close(w.stopCh)
+	<-w.stopCh
+}
@@ -5,27 +5,33 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/tibeahx/claimer/app/internal/config"
 	"github.com/tibeahx/claimer/app/internal/telegram"
+	"github.com/tibeahx/claimer/pkg/entity"
 	"github.com/tibeahx/claimer/pkg/log"
 )
 
 type Notifier struct {
-	handler                 *telegram.Handler
-	fn                      func(chatID int64, users ...string) error
-	standOwnershipThreshold time.Duration
-	stopCh                  chan struct{}
+	handler   *telegram.Handler
+	fn        func(chatID int64, tier config.ReminderTier, stands ...entity.Stand) error
+	reminders config.RemindersConfig
+	stopCh    chan struct{}
+	// fires when reminders deferred till working hours are due
+	deferred <-chan time.Time
+	// fires when the nearest auto release is due
+	release <-chan time.Time
 }
 
 func NewNotifier(
 	handler *telegram.Handler,
-	notifyFn func(chatID int64, users ...string) error,
-	standOwnershipThreshold time.Duration,
+	notifyFn func(chatID int64, tier config.ReminderTier, stands ...entity.Stand) error,
+	reminders config.RemindersConfig,
 ) *Notifier {
 	return &Notifier{
-		handler:                 handler,
-		fn:                      notifyFn,
-		standOwnershipThreshold: standOwnershipThreshold,
-		stopCh:                  make(chan struct{}, 1),
+		handler:   handler,
+		fn:        notifyFn,
+		reminders: reminders,
+		stopCh:    make(chan struct{}, 1),
 	}
 }
 
@@ -42,34 +48,83 @@ func (w *Notifier) Start(ctx context.Context, interval time.Duration) {
 			log.WithSource(log.Zap().Desugar(), "notifier").Info("received stop signal")
 			return
 		case <-ticker.C:
-			if err := w.execNotify(); err != nil {
-				log.WithSource(log.Zap().Desugar(), "notifier").
-					Sugar().
-					Errorf("checkStands failed in worker due to %w", err)
-				continue
-			}
+		case <-w.deferred:
+			w.deferred = nil
+		case <-w.release:
+			w.release = nil
+		}
+
+		if err := w.execNotify(); err != nil {
+			log.WithSource(log.Zap().Desugar(), "notifier").
+				Sugar().
+				Errorf("checkStands failed in worker due to %w", err)
 		}
 	}
 }
 
 func (w *Notifier) execNotify() error {
+	var (
+		chatID = w.handler.TeamChatID()
+		now    = time.Now()
+	)
+
+	// the bot hasn't been added to the team chat yet
+	if chatID == 0 {
+		return nil
+	}
+
+	// stands whose owners didn't keep them after the release warning, they
+	// are released at the time promised whatever working hours are
+	if err := w.handler.AutoRelease(chatID); err != nil {
+		return fmt.Errorf("failed to auto release stands: %w", err)
+	}
+
 	stands, err := w.handler.Repo().Stands()
 	if err != nil {
 		return fmt.Errorf("failed to get stands: %w", err)
 	}
 
-	usersToNotify := make([]string, 0)
+	w.scheduleRelease(stands, now)
+
+	// reminders wait for working hours of the chat
+	if next := w.handler.ChatHours(chatID).Next(now); next.After(now) {
+		if w.deferred == nil {
+			log.WithSource(log.Zap().Desugar(), "notifier").
+				Sugar().
+				Infof("reminders are deferred till %s", next.Format(time.RFC3339))
+			w.deferred = time.After(next.Sub(now))
+		}
+		return nil
+	}
+
+	var (
+		tiers = make([]config.ReminderTier, 0)
+		due   = make(map[config.ReminderTier][]entity.Stand)
+	)
 
 	for _, stand := range stands {
-		if !stand.Released && stand.OwnerUsername.String != "" {
-			if time.Since(stand.TimeClaimed.Time) >= w.standOwnershipThreshold {
-				usersToNotify = append(usersToNotify, stand.OwnerUsername.String)
-			}
+		if stand.Released || stand.OwnerUsername.String == "" {
+			continue
 		}
+
+		// owner has acknowledged the reminder
+		if stand.ReminderAckUntil.Valid && now.Before(stand.ReminderAckUntil.Time) {
+			continue
+		}
+
+		tier, ok := w.dueTier(stand, w.handler.Held(chatID, stand.TimeClaimed.Time, now))
+		if !ok {
+			continue
+		}
+
+		if _, ok := due[tier]; !ok {
+			tiers = append(tiers, tier)
+		}
+		due[tier] = append(due[tier], stand)
 	}
 
-	if len(usersToNotify) > 0 {
-		if err := w.fn(telegram.ChatInfo.ChatID, usersToNotify...); err != nil {
+	for _, tier := range tiers {
+		if err := w.fn(chatID, tier, due[tier]...); err != nil {
 			return fmt.Errorf("failed to notify users: %w", err)
 		}
 	}
@@ -77,6 +132,51 @@ func (w *Notifier) execNotify() error {
 	return nil
 }
 
+// scheduleRelease wakes the notifier up when the nearest auto release is
+// due, so it doesn't wait for the next check
+func (w *Notifier) scheduleRelease(stands []entity.Stand, now time.Time) {
+	var at time.Time
+
+	for _, stand := range stands {
+		// past ones have just been released
+		if stand.Released || !stand.ReleaseAt.Valid || !stand.ReleaseAt.Time.After(now) {
+			continue
+		}
+		if at.IsZero() || stand.ReleaseAt.Time.Before(at) {
+			at = stand.ReleaseAt.Time
+		}
+	}
+
+	if at.IsZero() {
+		w.release = nil
+		return
+	}
+
+	w.release = time.After(at.Sub(now))
+}
+
+// dueTier returns the highest tier the stand held for so long has
+// reached, lower ones that were missed aren't fired anymore. False means
+// the tier has fired already
+func (w *Notifier) dueTier(stand entity.Stand, held time.Duration) (config.ReminderTier, bool) {
+	var (
+		due   config.ReminderTier
+		found bool
+	)
+
+	for _, tier := range w.reminders.For(stand.Name) {
+		if held >= tier.After {
+			due, found = tier, true
+		}
+	}
+
+	if !found || due.Level <= stand.ReminderTier {
+		return config.ReminderTier{}, false
+	}
+
+	return due, true
+}
+
 func (w *Notifier) Stop() {
 	w.stopCh <- struct{}{}
 	close(w.stopCh)
@@ -0,0 +1,45 @@
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tibeahx/claimer/app/internal/config"
+	"github.com/tibeahx/claimer/pkg/entity"
+)
+
+func TestNotifierDueTier(t *testing.T) {
+	tiers := []config.ReminderTier{
+		{After: 24 * time.Hour, Notify: config.NotifyOwner, Level: 1},
+		{After: 48 * time.Hour, Notify: config.NotifyGroup, Level: 2},
+		{After: 72 * time.Hour, Notify: config.NotifyAdmins, Level: 3},
+	}
+
+	w := &Notifier{reminders: config.RemindersConfig{Tiers: tiers}}
+
+	tests := []struct {
+		name   string
+		held   time.Duration
+		fired  int
+		want   int
+		wantOK bool
+	}{
+		{name: "too early", held: 23 * time.Hour},
+		{name: "first tier", held: 24 * time.Hour, want: 1, wantOK: true},
+		{name: "first tier fired", held: 30 * time.Hour, fired: 1},
+		{name: "second tier", held: 50 * time.Hour, fired: 1, want: 2, wantOK: true},
+		{name: "missed tiers are skipped", held: 80 * time.Hour, want: 3, wantOK: true},
+		{name: "all fired", held: 100 * time.Hour, fired: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stand := entity.Stand{Name: "dev", ReminderTier: tt.fired}
+
+			tier, ok := w.dueTier(stand, tt.held)
+			if ok != tt.wantOK || tier.Level != tt.want {
+				t.Errorf("got tier %d %t, want %d %t", tier.Level, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package workhours
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid working hours")
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Hours are working hours of a team, zero value means any time is
+// working time
+type Hours struct {
+	// since midnight, From is before To
+	From time.Duration
+	To   time.Duration
+	// every day if empty
+	Days     []time.Weekday
+	Location *time.Location
+}
+
+// New parses hours given as HH:MM-HH:MM, weekdays as mon, tue, ..., sun
+// and IANA time zone, local one if empty. Empty hours give zero Hours
+func New(hours string, days []string, timezone string) (Hours, error) {
+	if hours == "" {
+		return Hours{}, nil
+	}
+
+	var (
+		h   Hours
+		err error
+	)
+
+	from, to, ok := strings.Cut(hours, "-")
+	if !ok {
+		return Hours{}, fmt.Errorf("%w: %q", ErrInvalid, hours)
+	}
+
+	if h.From, err = parseClock(from); err != nil {
+		return Hours{}, err
+	}
+
+	if h.To, err = parseClock(to); err != nil {
+		return Hours{}, err
+	}
+
+	if h.From >= h.To {
+		return Hours{}, fmt.Errorf("%w: %q ends before it starts", ErrInvalid, hours)
+	}
+
+	if h.Days, err = ParseWeekdays(days); err != nil {
+		return Hours{}, err
+	}
+
+	h.Location = time.Local
+	if timezone != "" {
+		if h.Location, err = time.LoadLocation(timezone); err != nil {
+			return Hours{}, fmt.Errorf("%w: %w", ErrInvalid, err)
+		}
+	}
+
+	return h, nil
+}
+
+// Parse reads hours in the form String returns them, e.g.
+// "09:00-18:00 mon,tue,wed,thu,fri Europe/Moscow", weekdays and time
+// zone are optional
+func Parse(spec string) (Hours, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 || len(fields) > 3 {
+		return Hours{}, fmt.Errorf("%w: %q", ErrInvalid, spec)
+	}
+
+	var (
+		days     []string
+		timezone string
+	)
+
+	for _, field := range fields[1:] {
+		if _, ok := weekdays[strings.ToLower(strings.Split(field, ",")[0])]; ok && days == nil {
+			days = strings.Split(field, ",")
+			continue
+		}
+		if timezone != "" {
+			return Hours{}, fmt.Errorf("%w: %q", ErrInvalid, spec)
+		}
+		timezone = field
+	}
+
+	return New(fields[0], days, timezone)
+}
+
+// ParseWeekdays parses mon, tue, ..., sun in any case
+func ParseWeekdays(days []string) ([]time.Weekday, error) {
+	parsed := make([]time.Weekday, 0, len(days))
+
+	for _, day := range days {
+		weekday, ok := weekdays[strings.ToLower(strings.TrimSpace(day))]
+		if !ok {
+			return nil, fmt.Errorf("%w: weekday %q", ErrInvalid, day)
+		}
+		parsed = append(parsed, weekday)
+	}
+
+	return parsed, nil
+}
+
+func (h Hours) IsZero() bool {
+	return h.From == 0 && h.To == 0
+}
+
+// Open reports whether t is working time
+func (h Hours) Open(t time.Time) bool {
+	if h.IsZero() {
+		return true
+	}
+
+	t = t.In(h.Location)
+	if !h.workday(t.Weekday()) {
+		return false
+	}
+
+	since := t.Sub(midnight(t))
+
+	return since >= h.From && since < h.To
+}
+
+// Next returns t if it's working time, otherwise the start of the next
+// working period
+func (h Hours) Next(t time.Time) time.Time {
+	if h.Open(t) {
+		return t
+	}
+
+	t = t.In(h.Location)
+
+	for date := midnight(t); ; date = date.AddDate(0, 0, 1) {
+		start := date.Add(h.From)
+		if h.workday(date.Weekday()) && start.After(t) {
+			return start
+		}
+	}
+}
+
+// Working returns working time between from and to
+func (h Hours) Working(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	if h.IsZero() {
+		return to.Sub(from)
+	}
+
+	var total time.Duration
+
+	from, to = from.In(h.Location), to.In(h.Location)
+
+	for date := midnight(from); date.Before(to); date = date.AddDate(0, 0, 1) {
+		if !h.workday(date.Weekday()) {
+			continue
+		}
+
+		start, end := date.Add(h.From), date.Add(h.To)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+
+	return total
+}
+
+// String formats hours the way Parse reads them
+func (h Hours) String() string {
+	if h.IsZero() {
+		return ""
+	}
+
+	parts := []string{formatClock(h.From) + "-" + formatClock(h.To)}
+
+	if len(h.Days) > 0 {
+		days := make([]string, 0, len(h.Days))
+		for _, day := range h.Days {
+			days = append(days, strings.ToLower(day.String()[:3]))
+		}
+		parts = append(parts, strings.Join(days, ","))
+	}
+
+	if h.Location != nil && h.Location != time.Local {
+		parts = append(parts, h.Location.String())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (h Hours) workday(day time.Weekday) bool {
+	return len(h.Days) == 0 || slices.Contains(h.Days, day)
+}
+
+// parseClock parses HH:MM, 24:00 is the end of the day
+func parseClock(clock string) (time.Duration, error) {
+	hh, mm, ok := strings.Cut(clock, ":")
+
+	hours, errH := strconv.Atoi(hh)
+	minutes, errM := strconv.Atoi(mm)
+
+	if !ok || errH != nil || errM != nil || hours < 0 || minutes < 0 || minutes > 59 ||
+		hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("%w: time %q", ErrInvalid, clock)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,154 @@
+package workhours
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// 2024-05-06 is Monday
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 5, day, hour, minute, 0, 0, time.UTC)
+}
+
+func mustNew(t *testing.T, hours string, days ...string) Hours {
+	t.Helper()
+
+	h, err := New(hours, days, "UTC")
+	if err != nil {
+		t.Fatalf("New(%q, %q) failed: %v", hours, days, err)
+	}
+
+	return h
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "09:00-18:00", want: "09:00-18:00"},
+		{spec: "09:00-18:00 mon,tue,wed", want: "09:00-18:00 mon,tue,wed"},
+		{spec: "9:00-18:30 MON,Fri UTC", want: "09:00-18:30 mon,fri UTC"},
+		{spec: "09:00-18:00 UTC mon", want: "09:00-18:00 mon UTC"},
+		{spec: "00:00-24:00", want: "00:00-24:00"},
+		{spec: "", wantErr: true},
+		{spec: "09:00", wantErr: true},
+		{spec: "18:00-09:00", wantErr: true},
+		{spec: "09:00-09:00", wantErr: true},
+		{spec: "09:60-18:00", wantErr: true},
+		{spec: "24:30-25:00", wantErr: true},
+		{spec: "09:00-18:00 mon,funday", wantErr: true},
+		{spec: "09:00-18:00 Nowhere/City", wantErr: true},
+		{spec: "09:00-18:00 UTC UTC", wantErr: true},
+		{spec: "09:00-18:00 mon UTC extra", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			h, err := Parse(tt.spec)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("got error %v, want %v", err, ErrInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			if got := h.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			again, err := Parse(h.String())
+			if err != nil || again.String() != h.String() {
+				t.Errorf("String() doesn't parse back: %q, %v", again.String(), err)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	weekdays := mustNew(t, "09:00-18:00", "mon", "tue", "wed", "thu", "fri")
+
+	tests := []struct {
+		name  string
+		hours Hours
+		t     time.Time
+		want  bool
+	}{
+		{name: "zero hours", hours: Hours{}, t: at(5, 3, 0), want: true},
+		{name: "start", hours: weekdays, t: at(6, 9, 0), want: true},
+		{name: "before start", hours: weekdays, t: at(6, 8, 59), want: false},
+		{name: "end is closed", hours: weekdays, t: at(6, 18, 0), want: false},
+		{name: "weekend", hours: weekdays, t: at(5, 12, 0), want: false},
+		{name: "other time zone", hours: weekdays, t: at(6, 9, 30).In(time.FixedZone("UTC+3", 3*3600)), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.Open(tt.t); got != tt.want {
+				t.Errorf("Open(%s) = %t, want %t", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	weekdays := mustNew(t, "09:00-18:00", "mon", "tue", "wed", "thu", "fri")
+	daily := mustNew(t, "09:00-18:00")
+
+	tests := []struct {
+		name  string
+		hours Hours
+		t     time.Time
+		want  time.Time
+	}{
+		{name: "zero hours", hours: Hours{}, t: at(5, 3, 0), want: at(5, 3, 0)},
+		{name: "working time", hours: weekdays, t: at(6, 12, 0), want: at(6, 12, 0)},
+		{name: "morning", hours: weekdays, t: at(6, 7, 0), want: at(6, 9, 0)},
+		{name: "evening", hours: daily, t: at(6, 20, 0), want: at(7, 9, 0)},
+		{name: "after midnight", hours: daily, t: at(7, 0, 30), want: at(7, 9, 0)},
+		{name: "friday evening", hours: weekdays, t: at(10, 18, 0), want: at(13, 9, 0)},
+		{name: "weekend", hours: weekdays, t: at(11, 12, 0), want: at(13, 9, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.Next(tt.t); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorking(t *testing.T) {
+	weekdays := mustNew(t, "09:00-18:00", "mon", "tue", "wed", "thu", "fri")
+	daily := mustNew(t, "09:00-18:00")
+
+	tests := []struct {
+		name     string
+		hours    Hours
+		from, to time.Time
+		want     time.Duration
+	}{
+		{name: "zero hours", hours: Hours{}, from: at(6, 20, 0), to: at(7, 8, 0), want: 12 * time.Hour},
+		{name: "backwards", hours: daily, from: at(7, 12, 0), to: at(6, 12, 0), want: 0},
+		{name: "within a day", hours: daily, from: at(6, 10, 0), to: at(6, 12, 30), want: 150 * time.Minute},
+		{name: "outside hours", hours: daily, from: at(6, 18, 30), to: at(6, 23, 0), want: 0},
+		{name: "across midnight", hours: daily, from: at(6, 17, 0), to: at(7, 10, 0), want: 2 * time.Hour},
+		{name: "night only", hours: daily, from: at(6, 22, 0), to: at(7, 6, 0), want: 0},
+		{name: "whole days", hours: daily, from: at(6, 0, 0), to: at(8, 0, 0), want: 18 * time.Hour},
+		{name: "over weekend", hours: weekdays, from: at(10, 17, 0), to: at(13, 10, 0), want: 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.Working(tt.from, tt.to); got != tt.want {
+				t.Errorf("Working(%s, %s) = %s, want %s", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1 @@
+alter table chat_settings drop column if exists stands_thread_id;
\ No newline at end of file
@@ -0,0 +1 @@
+alter table chat_settings add column if not exists stands_thread_id bigint;
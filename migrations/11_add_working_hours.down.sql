@@ -0,0 +1 @@
+alter table chat_settings drop column if exists working_hours;
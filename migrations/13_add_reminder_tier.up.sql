@@ -0,0 +1,2 @@
+-- escalation tiers of reminders already fired for the current claim
+alter table stands add column if not exists reminder_tier integer not null default 0;
@@ -0,0 +1 @@
+alter table stands drop column if exists release_at;
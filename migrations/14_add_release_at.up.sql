@@ -0,0 +1,2 @@
+-- busy stand is released automatically then unless its owner keeps it
+alter table stands add column if not exists release_at timestamp;
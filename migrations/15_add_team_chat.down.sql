@@ -0,0 +1 @@
+alter table chat_settings drop column if exists seen_at;
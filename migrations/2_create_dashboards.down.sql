@@ -0,0 +1 @@
+drop table if exists dashboards;
\ No newline at end of file
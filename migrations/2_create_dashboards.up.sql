@@ -0,0 +1,5 @@
+create table if not exists dashboards (
+    chat_id bigint primary key,
+    message_id bigint not null,
+    updated timestamp
+);
@@ -0,0 +1,3 @@
+alter table users drop column if exists language;
+
+drop table if exists chat_settings;
@@ -0,0 +1,6 @@
+create table if not exists chat_settings (
+    chat_id bigint primary key,
+    language text
+);
+
+alter table users add column if not exists language text;
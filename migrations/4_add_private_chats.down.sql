@@ -0,0 +1,5 @@
+alter table users
+drop column if exists notify_private,
+drop column if exists private_chat,
+drop column if exists team_chat_id,
+drop column if exists user_id;
@@ -0,0 +1,5 @@
+alter table users
+add column if not exists user_id bigint,
+add column if not exists team_chat_id bigint,
+add column if not exists private_chat bool default false,
+add column if not exists notify_private bool default false;
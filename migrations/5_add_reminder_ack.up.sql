@@ -0,0 +1 @@
+alter table stands add column if not exists reminder_ack_until timestamp;
@@ -0,0 +1 @@
+drop table if exists transfers;
\ No newline at end of file
@@ -0,0 +1,9 @@
+create table if not exists transfers (
+    id bigserial primary key,
+    stand_name text not null references stands(name) on delete cascade,
+    from_username text not null,
+    to_username text not null,
+    status text not null default 'pending',
+    created timestamp not null default now(),
+    resolved timestamp
+);
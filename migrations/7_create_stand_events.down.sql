@@ -0,0 +1 @@
+drop table if exists stand_events;
\ No newline at end of file
@@ -0,0 +1,9 @@
+create table if not exists stand_events (
+    id bigserial primary key,
+    stand_name text not null references stands(name) on delete cascade,
+    kind text not null,
+    actor_username text,
+    owner_username text,
+    reason text,
+    created timestamp not null default now()
+);
@@ -0,0 +1,5 @@
+alter table chat_settings drop column if exists default_role;
+
+drop table if exists stand_allowlist;
+
+drop table if exists chat_roles;
@@ -0,0 +1,14 @@
+create table if not exists chat_roles (
+    chat_id bigint not null,
+    username text not null,
+    role text not null,
+    primary key (chat_id, username)
+);
+
+create table if not exists stand_allowlist (
+    stand_name text not null references stands(name) on delete cascade,
+    username text not null,
+    primary key (stand_name, username)
+);
+
+alter table chat_settings add column if not exists default_role text;
@@ -0,0 +1 @@
+drop table if exists menus;
\ No newline at end of file
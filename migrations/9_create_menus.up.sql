@@ -0,0 +1,9 @@
+create table if not exists menus (
+    chat_id bigint not null,
+    message_id bigint not null,
+    command_message_id bigint,
+    expires timestamp not null,
+    primary key (chat_id, message_id)
+);
+
+create index if not exists menus_expires_idx on menus (expires);
@@ -79,3 +79,21 @@ func scanRow[T any](rows *sqlx.Rows, dest *T) error {
 
 	return rows.Scan(dest)
 }
+
+// WithTx runs fn in a transaction, which is committed if fn succeeds
+// and rolled back otherwise
+func WithTx(db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin tx")
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.CombineErrors(err, rbErr)
+		}
+		return err
+	}
+
+	return errors.Wrap(tx.Commit(), "failed to commit tx")
+}
@@ -5,13 +5,15 @@ import (
 	"time"
 )
 
-type ChatInfo struct {
-	ChatID int64
-}
-
 type User struct {
-	Username string    `db:"username"`
-	Created  time.Time `db:"created"`
+	Username      string         `db:"username"`
+	Created       time.Time      `db:"created"`
+	Language      sql.NullString `db:"language"`
+	UserID        sql.NullInt64  `db:"user_id"`
+	TeamChatID    sql.NullInt64  `db:"team_chat_id"`
+	PrivateChat   bool           `db:"private_chat"`
+	NotifyPrivate bool           `db:"notify_private"`
+	QuietHours    sql.NullString `db:"quiet_hours"`
 }
 
 type Stand struct {
@@ -19,4 +21,93 @@ type Stand struct {
 	Released      bool           `db:"released,omitempty"`
 	OwnerUsername sql.NullString `db:"owner_username"`
 	TimeClaimed   sql.NullTime   `db:"time_claimed"`
+	// reminders about the stand are suppressed until then
+	ReminderAckUntil sql.NullTime `db:"reminder_ack_until"`
+	// the last tier of reminders fired for the current claim
+	ReminderTier int `db:"reminder_tier"`
+	// the stand is released automatically then unless owner keeps it
+	ReleaseAt sql.NullTime `db:"release_at"`
+}
+
+type Dashboard struct {
+	ChatID    int64     `db:"chat_id"`
+	MessageID int       `db:"message_id"`
+	Updated   time.Time `db:"updated"`
+}
+
+// Menu is a keyboard message sent in reply to a command, it's removed with
+// the command message after a choice is made or when it expires
+type Menu struct {
+	ChatID           int64         `db:"chat_id"`
+	MessageID        int           `db:"message_id"`
+	CommandMessageID sql.NullInt64 `db:"command_message_id"`
+	Expires          time.Time     `db:"expires"`
+}
+
+const (
+	TransferPending  = "pending"
+	TransferAccepted = "accepted"
+	TransferDeclined = "declined"
+)
+
+type Transfer struct {
+	ID           int64        `db:"id"`
+	StandName    string       `db:"stand_name"`
+	FromUsername string       `db:"from_username"`
+	ToUsername   string       `db:"to_username"`
+	Status       string       `db:"status"`
+	Created      time.Time    `db:"created"`
+	Resolved     sql.NullTime `db:"resolved"`
+}
+
+const (
+	EventForceRelease = "force_release"
+	// stand released because its owner left the chat
+	EventMemberLeft = "member_left"
+	// stand held past its hard limit released by the bot
+	EventAutoRelease = "auto_release"
+)
+
+// StandEvent records actions on stands done not by their owners
+type StandEvent struct {
+	ID            int64          `db:"id"`
+	StandName     string         `db:"stand_name"`
+	Kind          string         `db:"kind"`
+	ActorUsername sql.NullString `db:"actor_username"`
+	OwnerUsername sql.NullString `db:"owner_username"`
+	Reason        sql.NullString `db:"reason"`
+	Created       time.Time      `db:"created"`
+}
+
+const (
+	RoleViewer = "viewer"
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleMember: 2,
+	RoleAdmin:  3,
+}
+
+func IsRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleAtLeast reports whether role grants everything required one does
+func RoleAtLeast(role, required string) bool {
+	return roleRanks[role] >= roleRanks[required]
+}
+
+type ChatRole struct {
+	ChatID   int64  `db:"chat_id"`
+	Username string `db:"username"`
+	Role     string `db:"role"`
+}
+
+type StandAccess struct {
+	StandName string `db:"stand_name"`
+	Username  string `db:"username"`
 }